@@ -0,0 +1,103 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "github.com/karl247ai/lang-portal/internal/backup"
+    "github.com/karl247ai/lang-portal/internal/config"
+    "github.com/karl247ai/lang-portal/internal/repository"
+)
+
+const usage = `Usage: backup [-config file] [-db path] [-dir folder] <command>
+
+The database, backup folder and retention are those of the server, from
+the config file and environment, unless overridden by flags.
+
+Commands:
+  list            list the backups, newest first
+  create          write a manual backup of the database
+  check <name>    run an integrity check on a backup
+  restore <name>  replace the database with a backup; the current data is
+                  saved as a pre-restore backup first. Stop the server
+                  before restoring.
+`
+
+func main() {
+    configFile := flag.String("config", "", "path to a YAML config file, as for the server")
+    dbPath := flag.String("db", "", "path to the SQLite database, overriding db_path from the configuration")
+    dir := flag.String("dir", "", "folder containing the backups, overriding backup.dir from the configuration")
+    keep := flag.Int("keep", 0, "number of backups of each kind to keep, 0 keeps all; overrides backup.keep from the configuration")
+    flag.Usage = func() {
+        fmt.Fprint(os.Stderr, usage)
+        flag.PrintDefaults()
+    }
+    flag.Parse()
+
+    if flag.NArg() == 0 {
+        flag.Usage()
+        os.Exit(2)
+    }
+    name := func() string {
+        if flag.NArg() < 2 {
+            log.Fatalf("%s needs a backup name, see backup list", flag.Arg(0))
+        }
+        return flag.Arg(1)
+    }
+
+    cfg, err := config.LoadFile(*configFile, os.Getenv)
+    if err != nil {
+        log.Fatalf("Failed to load configuration: %v", err)
+    }
+    flag.Visit(func(f *flag.Flag) {
+        switch f.Name {
+        case "db":
+            cfg.DBPath = *dbPath
+        case "dir":
+            cfg.Backup.Dir = *dir
+        case "keep":
+            cfg.Backup.Keep = *keep
+        }
+    })
+
+    db, err := repository.OpenDB(cfg.DBPath)
+    if err != nil {
+        log.Fatalf("Failed to connect to database: %v", err)
+    }
+    defer db.Close()
+
+    backups := backup.NewManager(db, cfg.Backup.Dir, cfg.Backup.Keep)
+    ctx := context.Background()
+    switch flag.Arg(0) {
+    case "list":
+        list, err := backups.List()
+        if err != nil {
+            log.Fatalf("Failed to list backups: %v", err)
+        }
+        for _, b := range list {
+            fmt.Printf("%-60s %10d bytes  %s\n", b.Name, b.SizeBytes, b.CreatedAt)
+        }
+    case "create":
+        b, err := backups.Snapshot(ctx, "manual")
+        if err != nil {
+            log.Fatalf("Backup failed: %v", err)
+        }
+        log.Printf("Wrote backup %s (%d bytes)", b.Name, b.SizeBytes)
+    case "check":
+        if err := backups.Check(ctx, name()); err != nil {
+            log.Fatalf("Backup is damaged: %v", err)
+        }
+        log.Printf("Backup %s is intact", flag.Arg(1))
+    case "restore":
+        saved, err := backups.Restore(ctx, name())
+        if err != nil {
+            log.Fatalf("Restore failed: %v", err)
+        }
+        log.Printf("Restored %s; the previous data was saved as %s", flag.Arg(1), saved.Name)
+    default:
+        flag.Usage()
+        os.Exit(2)
+    }
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "strconv"
+    "github.com/karl247ai/lang-portal/internal/config"
+    "github.com/karl247ai/lang-portal/internal/migrate"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/migrations"
+)
+
+const usage = `Usage: migrate [-config file] [-db path] <command>
+
+The database is the one the server uses: db_path from the config file or
+DATABASE_PATH, unless -db is given.
+
+Commands:
+  up          apply all pending migrations
+  down [n]    revert the last n applied migrations (default 1)
+  status      list migrations and whether they are applied
+`
+
+func main() {
+    configFile := flag.String("config", "", "path to a YAML config file, as for the server")
+    dbPath := flag.String("db", "", "path to the SQLite database, overriding db_path from the configuration")
+    flag.Usage = func() {
+        fmt.Fprint(os.Stderr, usage)
+        flag.PrintDefaults()
+    }
+    flag.Parse()
+
+    if flag.NArg() == 0 {
+        flag.Usage()
+        os.Exit(2)
+    }
+
+    cfg, err := config.LoadFile(*configFile, os.Getenv)
+    if err != nil {
+        log.Fatalf("Failed to load configuration: %v", err)
+    }
+    if *dbPath != "" {
+        cfg.DBPath = *dbPath
+    }
+
+    db, err := repository.OpenDB(cfg.DBPath)
+    if err != nil {
+        log.Fatalf("Failed to connect to database: %v", err)
+    }
+    defer db.Close()
+
+    migrator, err := migrate.New(db, migrations.FS)
+    if err != nil {
+        log.Fatalf("Failed to load migrations: %v", err)
+    }
+
+    ctx := context.Background()
+    switch flag.Arg(0) {
+    case "up":
+        applied, err := migrator.Up(ctx)
+        for _, m := range applied {
+            log.Printf("Applied %s", m.Name)
+        }
+        if err != nil {
+            log.Fatalf("Migration failed: %v", err)
+        }
+        if len(applied) == 0 {
+            log.Printf("Database is up to date")
+        }
+    case "down":
+        steps := 1
+        if flag.NArg() > 1 {
+            steps, err = strconv.Atoi(flag.Arg(1))
+            if err != nil || steps < 1 {
+                log.Fatalf("Invalid number of steps %q", flag.Arg(1))
+            }
+        }
+        reverted, err := migrator.Down(ctx, steps)
+        for _, m := range reverted {
+            log.Printf("Reverted %s", m.Name)
+        }
+        if err != nil {
+            log.Fatalf("Migration failed: %v", err)
+        }
+    case "status":
+        statuses, err := migrator.Status(ctx)
+        if err != nil {
+            log.Fatalf("Failed to read migration status: %v", err)
+        }
+        for _, s := range statuses {
+            state := "pending"
+            if s.Applied {
+                state = "applied " + s.AppliedAt
+            }
+            fmt.Printf("%-45s %s\n", s.Name, state)
+        }
+        if err := migrator.Verify(ctx); err != nil {
+            log.Fatalf("Schema drift detected: %v", err)
+        }
+    default:
+        flag.Usage()
+        os.Exit(2)
+    }
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+    "context"
+    "flag"
+    "log"
+    "os"
+    "github.com/karl247ai/lang-portal/internal/config"
+    "github.com/karl247ai/lang-portal/internal/migrate"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/seed"
+    "github.com/karl247ai/lang-portal/migrations"
+)
+
+func main() {
+    configFile := flag.String("config", "", "path to a YAML config file, as for the server")
+    dbPath := flag.String("db", "", "path to the SQLite database, overriding db_path from the configuration")
+    dir := flag.String("dir", "", "folder containing the seed files, overriding seed_dir from the configuration")
+    manifest := flag.String("manifest", seed.DefaultManifest, "manifest file inside the seed folder")
+    flag.Parse()
+
+    // Seed the database the server uses, with its duplicate policy
+    cfg, err := config.LoadFile(*configFile, os.Getenv)
+    if err != nil {
+        log.Fatalf("Failed to load configuration: %v", err)
+    }
+    if *dbPath != "" {
+        cfg.DBPath = *dbPath
+    }
+    if *dir != "" {
+        cfg.SeedDir = *dir
+    }
+    duplicatePolicy, err := models.ParseDuplicatePolicy(cfg.DuplicatePolicy)
+    if err != nil {
+        log.Fatalf("Failed to configure duplicate words: %v", err)
+    }
+
+    db, err := repository.OpenDB(cfg.DBPath)
+    if err != nil {
+        log.Fatalf("Failed to connect to database: %v", err)
+    }
+    defer db.Close()
+
+    ctx := context.Background()
+
+    // Seeding a fresh database should not require a separate migrate run
+    migrator, err := migrate.New(db, migrations.FS)
+    if err != nil {
+        log.Fatalf("Failed to load migrations: %v", err)
+    }
+    applied, err := migrator.Up(ctx)
+    for _, m := range applied {
+        log.Printf("Applied migration %s", m.Name)
+    }
+    if err != nil {
+        log.Fatalf("Failed to run migrations: %v", err)
+    }
+    if _, err := repository.BackfillNormKeys(ctx, db); err != nil {
+        log.Fatalf("Failed to normalize word text: %v", err)
+    }
+
+    importer := seed.NewImporter(db, os.DirFS(cfg.SeedDir))
+    importer.SetDuplicatePolicy(duplicatePolicy)
+    result, err := importer.Run(ctx, *manifest)
+    if err != nil {
+        log.Fatalf("Seeding failed: %v", err)
+    }
+
+    for _, invalid := range result.Invalid {
+        log.Printf("Skipped invalid entry %v", invalid)
+    }
+    log.Printf("Seeding complete: %d inserted, %d updated, %d skipped",
+        result.Inserted, result.Updated, result.Skipped)
+}
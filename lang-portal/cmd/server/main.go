@@ -1,15 +1,31 @@
 package main
 
 import (
-    "log"
+    "context"
+    "log/slog"
     "github.com/gin-gonic/gin"
     _ "github.com/karl247ai/lang-portal/docs" // swagger docs
     swaggerFiles "github.com/swaggo/files"
     ginSwagger "github.com/swaggo/gin-swagger"
     "net/http"
+    "os"
+    "os/signal"
+    "syscall"
+    "github.com/karl247ai/lang-portal/internal/backup"
+    "github.com/karl247ai/lang-portal/internal/config"
+    "github.com/karl247ai/lang-portal/internal/health"
+    "github.com/karl247ai/lang-portal/internal/logging"
+    "github.com/karl247ai/lang-portal/internal/metrics"
+    "github.com/karl247ai/lang-portal/internal/models"
     "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/seed"
     "github.com/karl247ai/lang-portal/internal/api/handlers"
-    "github.com/karl247ai/lang-portal/internal/api/middleware"
+    "github.com/karl247ai/lang-portal/internal/service"
+    "github.com/karl247ai/lang-portal/internal/srs"
+    "github.com/karl247ai/lang-portal/internal/tracing"
+    "github.com/karl247ai/lang-portal/internal/middleware"
+    "github.com/karl247ai/lang-portal/internal/migrate"
+    "github.com/karl247ai/lang-portal/migrations"
 )
 
 // @title           Language Learning Portal API
@@ -18,43 +34,229 @@ import (
 // @host           localhost:8080
 // @BasePath       /api/v1
 func main() {
+    cfg, err := config.Load(os.Args[1:], os.Getenv)
+    if err != nil {
+        fatal("failed to load configuration", err)
+    }
+    logger, err := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+    if err != nil {
+        fatal("failed to configure logging", err)
+    }
+    // Also routes the standard log package, used by libraries, through logger
+    slog.SetDefault(logger)
+
     // Initialize database
-    db, err := repository.NewDB()
+    db, err := repository.OpenDB(cfg.DBPath)
     if err != nil {
-        log.Fatalf("Failed to connect to database: %v", err)
+        fatal("failed to connect to database", err)
     }
-    defer db.Close()
 
-    wordRepo := repository.NewWordRepository(db)
-    wordHandler := handlers.NewWordHandler(wordRepo)
+    migrator, err := migrate.New(db, migrations.FS)
+    if err != nil {
+        fatal("failed to load migrations", err)
+    }
+    applied, err := migrator.Up(context.Background())
+    if err != nil {
+        fatal("failed to migrate database", err)
+    }
+    for _, m := range applied {
+        logger.Info("applied migration", "name", m.Name)
+    }
+    // Words written before normalized text existed, or restored from an old
+    // backup, get their duplicate keys once here instead of on every write.
+    // Words written with plain SQL while the server runs wait for a restart
+    if n, err := repository.BackfillNormKeys(context.Background(), db); err != nil {
+        fatal("failed to normalize word text", err)
+    } else if n > 0 {
+        logger.Info("normalized word text", "words", n)
+    }
 
-    r := gin.Default()
-    r.Use(middleware.ErrorHandler())
+    metrics.ObserveQueries()
+    repository.Observe(logging.NewQueryLogger(cfg.SlowQueryThreshold))
+    shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.Endpoint, cfg.Tracing.ServiceName, health.Version)
+    if err != nil {
+        fatal("failed to configure tracing", err)
+    }
+    if cfg.Tracing.Exporter != tracing.ExporterNone {
+        tracing.TraceQueries()
+    }
+    if err := metrics.RegisterDB(db); err != nil {
+        fatal("failed to register database metrics", err)
+    }
+    store := repository.NewStore(db)
+    duplicatePolicy, err := models.ParseDuplicatePolicy(cfg.DuplicatePolicy)
+    if err != nil {
+        fatal("failed to configure duplicate words", err)
+    }
+    store.SetDuplicatePolicy(duplicatePolicy)
+    repos := store.Repos()
+    wordHandler := handlers.NewWordHandler(repos.Words)
+    groupHandler := handlers.NewGroupHandler(repos.Groups)
+    algorithm, err := srs.New(cfg.SRSAlgorithm)
+    if err != nil {
+        fatal("failed to configure spaced repetition", err)
+    }
+    scheduler := service.NewSchedulerService(repos.Reviews, algorithm)
+    reviewHandler := handlers.NewReviewHandler(scheduler)
+    studySessionHandler := handlers.NewStudySessionHandler(repos.Sessions, store, scheduler)
+    studyActivityRepo := repository.NewStudyActivityRepository(db)
+    studyActivityHandler := handlers.NewStudyActivityHandler(studyActivityRepo, repos.Sessions)
+    // Streak days follow the configured zone unless a request passes ?tz=
+    loc, err := cfg.Location()
+    if err != nil {
+        fatal("failed to load time zone", err)
+    }
+    dashboardService := service.NewDashboardService(repository.NewDashboardRepository(db), loc)
+    dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+    transliterateHandler := handlers.NewTransliterateHandler()
+    backups := backup.NewManager(db, cfg.Backup.Dir, cfg.Backup.Keep)
+    backupHandler := handlers.NewBackupHandler(backups)
+    // SIGINT and SIGTERM stop the server; a second signal kills it
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+    backupsDone := make(chan struct{})
+    go func() {
+        defer close(backupsDone)
+        if cfg.Backup.Interval > 0 {
+            backups.Run(ctx, cfg.Backup.Interval)
+        }
+    }()
+    seeds := seed.NewImporter(db, os.DirFS(cfg.SeedDir))
+    seeds.SetDuplicatePolicy(duplicatePolicy)
+    maintenanceService := service.NewMaintenanceService(
+        repository.NewMaintenanceRepository(db),
+        backups,
+        seeds,
+    )
+    maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService)
+    healthChecker := health.NewChecker(db, migrator, cfg.DBPath, cfg.Health.Timeout, uint64(cfg.Health.MinFreeMB)<<20)
+    healthHandler := handlers.NewHealthHandler(healthChecker)
+
+    handlers.DefaultPageSize = cfg.Pagination.DefaultLimit
+    handlers.MaxPageSize = cfg.Pagination.MaxLimit
+
+    if cfg.LogLevel != "debug" {
+        gin.SetMode(gin.ReleaseMode)
+    }
+    r := gin.New()
+    r.Use(middleware.RequestID(logger))
+    if cfg.Tracing.Exporter != tracing.ExporterNone {
+        r.Use(tracing.Middleware())
+    }
+    r.Use(metrics.Middleware())
     r.Use(middleware.Logger())
+    // Inside the tracing, metrics and logging middleware, so a recovered
+    // panic is counted and logged as the 500 it becomes
+    r.Use(gin.Recovery())
+    r.Use(middleware.ErrorHandler())
+    r.Use(middleware.CORS(cfg.CORSOrigins))
+    r.Use(middleware.RateLimit(cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.Burst))
     
     // Add Swagger documentation
     r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
     
-    // Health check route
-    r.GET("/health", func(c *gin.Context) {
-        c.JSON(http.StatusOK, gin.H{
-            "status": "ok",
-            "message": "Language Portal API is running",
-            "database": "connected",
-        })
-    })
+    r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+    // Probes; /health is kept for clients of the old check
+    r.GET("/healthz", healthHandler.Live)
+    r.GET("/readyz", healthHandler.Ready)
+    r.GET("/health", healthHandler.Ready)
 
     // Word routes
     v1 := r.Group("/api/v1")
     {
         v1.GET("/words", wordHandler.GetWords)
         v1.POST("/words", wordHandler.CreateWord)
-        v1.PUT("/api/v1/words/:id", wordHandler.UpdateWord)
-        v1.DELETE("/api/v1/words/:id", wordHandler.DeleteWord)
+        v1.POST("/words/import", wordHandler.ImportWords)
+        v1.GET("/words/export", wordHandler.ExportWords)
+        v1.GET("/words/duplicates", wordHandler.GetDuplicates)
+        v1.GET("/words/:id", wordHandler.GetWord)
+        v1.PUT("/words/:id", wordHandler.UpdateWord)
+        v1.DELETE("/words/:id", wordHandler.DeleteWord)
+        v1.POST("/words/:id/merge", wordHandler.MergeWords)
+
+        v1.GET("/groups", groupHandler.GetGroups)
+        v1.POST("/groups", groupHandler.CreateGroup)
+        v1.GET("/groups/:id", groupHandler.GetGroup)
+        v1.PUT("/groups/:id", groupHandler.UpdateGroup)
+        v1.DELETE("/groups/:id", groupHandler.DeleteGroup)
+        v1.GET("/groups/:id/words", groupHandler.GetGroupWords)
+        v1.POST("/groups/:id/words", groupHandler.AddGroupWords)
+        v1.DELETE("/groups/:id/words/:word_id", groupHandler.RemoveGroupWord)
+
+        v1.GET("/study_sessions", studySessionHandler.GetStudySessions)
+        v1.POST("/study_sessions", studySessionHandler.CreateStudySession)
+        v1.GET("/study_sessions/:id", studySessionHandler.GetStudySession)
+        v1.GET("/study_sessions/:id/words", studySessionHandler.GetStudySessionWords)
+        v1.POST("/study_sessions/:id/words/:word_id/review", studySessionHandler.ReviewWord)
+
+        v1.GET("/study_activities", studyActivityHandler.GetStudyActivities)
+        v1.POST("/study_activities", studyActivityHandler.LaunchStudyActivity)
+        v1.GET("/study_activities/:id", studyActivityHandler.GetStudyActivity)
+        v1.GET("/study_activities/:id/study_sessions", studyActivityHandler.GetStudyActivitySessions)
+
+        v1.GET("/dashboard/last_study_session", dashboardHandler.GetLastStudySession)
+        v1.GET("/dashboard/study_progress", dashboardHandler.GetStudyProgress)
+        v1.GET("/dashboard/quick-stats", dashboardHandler.GetQuickStats)
+
+        v1.GET("/reviews/due", reviewHandler.GetDueWords)
+
+        v1.POST("/transliterate", transliterateHandler.Transliterate)
+
+        v1.POST("/reset_history", maintenanceHandler.ResetHistory)
+        v1.POST("/full_reset", maintenanceHandler.FullReset)
+
+        v1.GET("/admin/backups", backupHandler.GetBackups)
+        v1.POST("/admin/backups", backupHandler.CreateBackup)
     }
     
-    log.Printf("Server starting on http://localhost:8080")
-    if err := r.Run(":8080"); err != nil {
-        log.Fatalf("Failed to start server: %v", err)
+    srv := &http.Server{
+        Addr:         cfg.ListenAddr,
+        Handler:      r,
+        ReadTimeout:  cfg.Server.ReadTimeout,
+        WriteTimeout: cfg.Server.WriteTimeout,
+        IdleTimeout:  cfg.Server.IdleTimeout,
+    }
+    serveErr := make(chan error, 1)
+    go func() {
+        logger.Info("server starting", "addr", cfg.ListenAddr)
+        serveErr <- srv.ListenAndServe()
+    }()
+
+    select {
+    case err := <-serveErr:
+        fatal("failed to start server", err)
+    case <-ctx.Done():
+    }
+    stop()
+
+    logger.Info("shutting down, waiting for in-flight requests", "timeout", cfg.Server.ShutdownTimeout.String())
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+    defer cancel()
+    if err := srv.Shutdown(shutdownCtx); err != nil {
+        logger.Warn("requests still running at the shutdown timeout were cut off", "timeout", cfg.Server.ShutdownTimeout.String(), "error", err)
+        srv.Close()
+    } else {
+        logger.Info("all requests finished")
     }
+
+    // Flush the spans still buffered
+    tracingCtx, cancelTracing := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+    defer cancelTracing()
+    if err := shutdownTracing(tracingCtx); err != nil {
+        logger.Warn("failed to flush traces", "error", err)
+    }
+
+    <-backupsDone
+    if err := repository.CloseDB(db); err != nil {
+        logger.Error("database closed after a failed checkpoint", "error", err)
+        os.Exit(1)
+    }
+    logger.Info("database checkpointed and closed, server stopped")
+}
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+    slog.Error(msg, "error", err)
+    os.Exit(1)
 }
\ No newline at end of file
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+    "net/http"
+    "github.com/gin-gonic/gin"
+    "github.com/karl247ai/lang-portal/internal/backup"
+)
+
+type BackupHandler struct {
+    backups *backup.Manager
+}
+
+func NewBackupHandler(backups *backup.Manager) *BackupHandler {
+    return &BackupHandler{backups: backups}
+}
+
+// GetBackups godoc
+// @Summary     List backups
+// @Description List the database backups in the backup folder, newest first
+// @Tags        admin
+// @Produce     json
+// @Success     200  {object}  models.BackupListResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /admin/backups [get]
+func (h *BackupHandler) GetBackups(c *gin.Context) {
+    backups, err := h.backups.List()
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": backups})
+}
+
+// CreateBackup godoc
+// @Summary     Create backup
+// @Description Snapshot the live database into the backup folder with the SQLite online backup API. The snapshot passes an integrity check before it is kept; older manual snapshots beyond the retention limit are deleted. Restore with the backup command while the server is stopped.
+// @Tags        admin
+// @Produce     json
+// @Success     201  {object}  models.BackupResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /admin/backups [post]
+func (h *BackupHandler) CreateBackup(c *gin.Context) {
+    b, err := h.backups.Snapshot(c.Request.Context(), "manual")
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{"data": b})
+}
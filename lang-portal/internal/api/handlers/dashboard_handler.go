@@ -0,0 +1,87 @@
+package handlers
+
+import (
+    "github.com/gin-gonic/gin"
+    "net/http"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/service"
+)
+
+type DashboardHandler struct {
+    service *service.DashboardService
+}
+
+func NewDashboardHandler(service *service.DashboardService) *DashboardHandler {
+    return &DashboardHandler{service: service}
+}
+
+// GetLastStudySession godoc
+// @Summary     Get last study session
+// @Description Get the most recent study session
+// @Tags        dashboard
+// @Accept      json
+// @Produce     json
+// @Success     200  {object}  models.StudySessionResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /dashboard/last_study_session [get]
+func (h *DashboardHandler) GetLastStudySession(c *gin.Context) {
+    session, err := h.service.LastStudySession(c.Request.Context())
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": session})
+}
+
+// GetStudyProgress godoc
+// @Summary     Get study progress
+// @Description Get the number of words studied out of all available words
+// @Tags        dashboard
+// @Accept      json
+// @Produce     json
+// @Success     200  {object}  models.StudyProgressResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /dashboard/study_progress [get]
+func (h *DashboardHandler) GetStudyProgress(c *gin.Context) {
+    progress, err := h.service.StudyProgress(c.Request.Context())
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": progress})
+}
+
+// GetQuickStats godoc
+// @Summary     Get quick stats
+// @Description Get success rate, session and group totals and the current study streak
+// @Tags        dashboard
+// @Accept      json
+// @Produce     json
+// @Param       tz   query     string  false  "IANA time zone used to count streak days, e.g. Asia/Tokyo"
+// @Success     200  {object}  models.QuickStatsResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /dashboard/quick-stats [get]
+func (h *DashboardHandler) GetQuickStats(c *gin.Context) {
+    var loc *time.Location
+    if tz := c.Query("tz"); tz != "" {
+        var err error
+        loc, err = time.LoadLocation(tz)
+        if err != nil {
+            c.Error(repository.Invalid("invalid time zone", map[string]string{"tz": "unknown time zone"}))
+            return
+        }
+    }
+
+    stats, err := h.service.QuickStats(c.Request.Context(), loc)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": stats})
+}
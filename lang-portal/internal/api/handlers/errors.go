@@ -0,0 +1,52 @@
+package handlers
+
+import (
+    "errors"
+    "reflect"
+    "strings"
+    "github.com/gin-gonic/gin/binding"
+    playground "github.com/go-playground/validator/v10"
+    "github.com/karl247ai/lang-portal/internal/repository"
+)
+
+func init() {
+    // Report binding failures by JSON field name rather than Go field name
+    if v, ok := binding.Validator.Engine().(*playground.Validate); ok {
+        v.RegisterTagNameFunc(func(f reflect.StructField) string {
+            name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+            if name == "-" {
+                return ""
+            }
+            return name
+        })
+    }
+}
+
+// invalidID reports a path parameter that is not a valid id.
+func invalidID(resource string) error {
+    return repository.Invalid("invalid "+resource+" id", nil)
+}
+
+// bindError turns a request body binding failure into a validation error
+// with one entry per failing field when the binding validator ran.
+func bindError(err error) error {
+    var verrs playground.ValidationErrors
+    if !errors.As(err, &verrs) {
+        return repository.Invalid("invalid request body: "+err.Error(), nil)
+    }
+
+    fields := map[string]string{}
+    for _, fe := range verrs {
+        switch fe.Tag() {
+        case "required":
+            fields[fe.Field()] = "required field"
+        default:
+            rule := fe.Tag()
+            if fe.Param() != "" {
+                rule += "=" + fe.Param()
+            }
+            fields[fe.Field()] = "must satisfy " + rule
+        }
+    }
+    return repository.Invalid("invalid request body", fields)
+}
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+    "github.com/gin-gonic/gin"
+    "net/http"
+    "strconv"
+    "strings"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "math"
+)
+
+type GroupHandler struct {
+    repo repository.GroupStore
+}
+
+func NewGroupHandler(repo repository.GroupStore) *GroupHandler {
+    return &GroupHandler{repo: repo}
+}
+
+// GetGroups godoc
+// @Summary     Get groups list
+// @Description Get paginated list of groups with their word counts
+// @Tags        groups
+// @Accept      json
+// @Produce     json
+// @Param       page  query    int  false  "Page number"
+// @Param       limit query    int  false  "Items per page"
+// @Success     200  {object}  models.PaginatedResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /groups [get]
+func (h *GroupHandler) GetGroups(c *gin.Context) {
+    page, limit, offset := pageParams(c)
+
+    groups, err := h.repo.GetGroups(c.Request.Context(), limit, offset)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalItems, err := h.repo.GetGroupsCount(c.Request.Context())
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+    response := models.PaginatedResponse{
+        Data: groups,
+        Pagination: models.PaginationMeta{
+            CurrentPage:  page,
+            TotalPages:   totalPages,
+            TotalItems:   totalItems,
+            ItemsPerPage: limit,
+        },
+    }
+
+    c.JSON(http.StatusOK, response)
+}
+
+// GetGroup godoc
+// @Summary     Get group
+// @Description Get a single group with its stats
+// @Tags        groups
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Group ID"
+// @Success     200  {object}  models.GroupDetailResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /groups/{id} [get]
+func (h *GroupHandler) GetGroup(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("group"))
+        return
+    }
+
+    group, err := h.repo.GetGroup(c.Request.Context(), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": group})
+}
+
+// CreateGroup godoc
+// @Summary     Create new group
+// @Description Add a new word group
+// @Tags        groups
+// @Accept      json
+// @Produce     json
+// @Param       group body      models.Group  true  "Group object"
+// @Success     201  {object}  models.GroupResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     409  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /groups [post]
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+    var group models.Group
+    if err := c.ShouldBindJSON(&group); err != nil {
+        c.Error(bindError(err))
+        return
+    }
+
+    group.Name = strings.TrimSpace(group.Name)
+    if group.Name == "" {
+        c.Error(repository.Invalid("name is required", map[string]string{"name": "required field"}))
+        return
+    }
+
+    if err := h.repo.CreateGroup(c.Request.Context(), &group); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{"data": group})
+}
+
+// UpdateGroup godoc
+// @Summary     Update group
+// @Description Rename an existing group
+// @Tags        groups
+// @Accept      json
+// @Produce     json
+// @Param       id    path      int          true  "Group ID"
+// @Param       group body      models.Group true  "Group object"
+// @Success     200  {object}  models.GroupResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     409  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /groups/{id} [put]
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("group"))
+        return
+    }
+
+    var group models.Group
+    if err := c.ShouldBindJSON(&group); err != nil {
+        c.Error(bindError(err))
+        return
+    }
+
+    group.Name = strings.TrimSpace(group.Name)
+    if group.Name == "" {
+        c.Error(repository.Invalid("name is required", map[string]string{"name": "required field"}))
+        return
+    }
+
+    if err := h.repo.UpdateGroup(c.Request.Context(), id, &group); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": group})
+}
+
+// DeleteGroup godoc
+// @Summary     Delete group
+// @Description Delete a group; its words stay in the vocabulary
+// @Tags        groups
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Group ID"
+// @Success     204  "No Content"
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /groups/{id} [delete]
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("group"))
+        return
+    }
+
+    if err := h.repo.DeleteGroup(c.Request.Context(), id); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
+
+// GetGroupWords godoc
+// @Summary     Get group words
+// @Description Get paginated list of words belonging to a group
+// @Tags        groups
+// @Accept      json
+// @Produce     json
+// @Param       id    path     int  true   "Group ID"
+// @Param       page  query    int  false  "Page number"
+// @Param       limit query    int  false  "Items per page"
+// @Success     200  {object}  models.PaginatedResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /groups/{id}/words [get]
+func (h *GroupHandler) GetGroupWords(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("group"))
+        return
+    }
+
+    if _, err := h.repo.GetGroup(c.Request.Context(), id); err != nil {
+        c.Error(err)
+        return
+    }
+
+    page, limit, offset := pageParams(c)
+
+    words, err := h.repo.GetGroupWords(c.Request.Context(), id, limit, offset)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalItems, err := h.repo.GetGroupWordsCount(c.Request.Context(), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+    response := models.PaginatedResponse{
+        Data: words,
+        Pagination: models.PaginationMeta{
+            CurrentPage:  page,
+            TotalPages:   totalPages,
+            TotalItems:   totalItems,
+            ItemsPerPage: limit,
+        },
+    }
+
+    c.JSON(http.StatusOK, response)
+}
+
+// AddGroupWords godoc
+// @Summary     Add words to group
+// @Description Link existing words to a group; words already in the group are ignored
+// @Tags        groups
+// @Accept      json
+// @Produce     json
+// @Param       id      path      int                       true  "Group ID"
+// @Param       request body      models.GroupWordsRequest  true  "Word IDs"
+// @Success     200  {object}  models.GroupDetailResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /groups/{id}/words [post]
+func (h *GroupHandler) AddGroupWords(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("group"))
+        return
+    }
+
+    var req models.GroupWordsRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.Error(bindError(err))
+        return
+    }
+
+    if len(req.WordIDs) == 0 {
+        c.Error(repository.Invalid("word_ids is required", map[string]string{"word_ids": "required field"}))
+        return
+    }
+
+    if err := h.repo.AddWords(c.Request.Context(), id, req.WordIDs); err != nil {
+        c.Error(err)
+        return
+    }
+
+    group, err := h.repo.GetGroup(c.Request.Context(), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": group})
+}
+
+// RemoveGroupWord godoc
+// @Summary     Remove word from group
+// @Description Unlink a word from a group; the word itself is kept
+// @Tags        groups
+// @Accept      json
+// @Produce     json
+// @Param       id      path      int  true  "Group ID"
+// @Param       word_id path      int  true  "Word ID"
+// @Success     204  "No Content"
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /groups/{id}/words/{word_id} [delete]
+func (h *GroupHandler) RemoveGroupWord(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("group"))
+        return
+    }
+
+    wordID, err := strconv.ParseInt(c.Param("word_id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("word"))
+        return
+    }
+
+    if err := h.repo.RemoveWord(c.Request.Context(), id, wordID); err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
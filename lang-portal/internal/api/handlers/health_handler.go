@@ -0,0 +1,45 @@
+package handlers
+
+import (
+    "net/http"
+    "github.com/gin-gonic/gin"
+    "github.com/karl247ai/lang-portal/internal/health"
+)
+
+type HealthHandler struct {
+    checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+    return &HealthHandler{checker: checker}
+}
+
+// Live godoc
+// @Summary     Liveness probe
+// @Description Report that the server process is running, with its build and uptime. The database is not touched, so a slow database does not get the server restarted. Served at the root, outside /api/v1.
+// @Tags        health
+// @Produce     json
+// @Success     200  {object}  models.HealthReport
+// @Router      /healthz [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+    c.JSON(http.StatusOK, h.checker.Live())
+}
+
+// Ready godoc
+// @Summary     Readiness probe
+// @Description Check that the database answers within the health timeout, that every migration is applied and unmodified, and that the database folder has the configured disk space available. Served at the root, outside /api/v1.
+// @Description Each check reports ok or fail in checks; the response is 503 when any of them failed.
+// @Tags        health
+// @Produce     json
+// @Success     200  {object}  models.HealthReport
+// @Failure     503  {object}  models.HealthReport
+// @Router      /readyz [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+    report := h.checker.Ready(c.Request.Context())
+
+    status := http.StatusOK
+    if report.Status != health.StatusOK {
+        status = http.StatusServiceUnavailable
+    }
+    c.JSON(status, report)
+}
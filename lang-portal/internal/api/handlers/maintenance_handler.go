@@ -0,0 +1,85 @@
+package handlers
+
+import (
+    "context"
+    "errors"
+    "io"
+    "net/http"
+    "time"
+    "github.com/gin-gonic/gin"
+    "github.com/karl247ai/lang-portal/internal/middleware"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/service"
+)
+
+type MaintenanceHandler struct {
+    maintenance *service.MaintenanceService
+}
+
+func NewMaintenanceHandler(maintenance *service.MaintenanceService) *MaintenanceHandler {
+    return &MaintenanceHandler{maintenance: maintenance}
+}
+
+// ResetHistory godoc
+// @Summary     Reset study history
+// @Description Delete every study session, review and review schedule, keeping words and groups. The database is backed up first.
+// @Description Resets take two requests: without a valid confirm token the server answers 428 with error.details.confirm_token, which confirms the reset when sent back as confirm within 5 minutes. Tokens are single-use.
+// @Tags        settings
+// @Accept      json
+// @Produce     json
+// @Param       request body      models.ResetRequest  false  "Confirmation"
+// @Success     200  {object}  models.ResetResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     428  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /reset_history [post]
+func (h *MaintenanceHandler) ResetHistory(c *gin.Context) {
+    h.reset(c, h.maintenance.ResetHistory)
+}
+
+// FullReset godoc
+// @Summary     Full reset
+// @Description Delete all study history, words and groups, then import the seed files again. Study activities are kept. The database is backed up first, and the reset is confirmed with a token as for POST /reset_history.
+// @Tags        settings
+// @Accept      json
+// @Produce     json
+// @Param       request body      models.ResetRequest  false  "Confirmation"
+// @Success     200  {object}  models.ResetResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     428  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /full_reset [post]
+func (h *MaintenanceHandler) FullReset(c *gin.Context) {
+    h.reset(c, h.maintenance.FullReset)
+}
+
+func (h *MaintenanceHandler) reset(c *gin.Context, run func(context.Context, string) (*models.ResetResult, error)) {
+    // An empty body asks for a confirmation token
+    var req models.ResetRequest
+    if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+        c.Error(bindError(err))
+        return
+    }
+
+    result, err := run(c.Request.Context(), req.Confirm)
+    var confirm *service.ConfirmationRequired
+    if errors.As(err, &confirm) {
+        c.Error(middleware.AppError{
+            Status:  http.StatusPreconditionRequired,
+            Code:    middleware.CodeConfirmationRequired,
+            Message: confirm.Error(),
+            Details: map[string]interface{}{
+                "action":        confirm.Action,
+                "confirm_token": confirm.Token,
+                "expires_at":    confirm.ExpiresAt.UTC().Format(time.RFC3339),
+            },
+        })
+        return
+    }
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": result})
+}
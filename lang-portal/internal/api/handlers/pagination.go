@@ -0,0 +1,31 @@
+package handlers
+
+import (
+    "github.com/gin-gonic/gin"
+    "strconv"
+)
+
+// Page sizes used by the list endpoints. main overrides them from the
+// pagination config at startup.
+var (
+    DefaultPageSize = 100
+    MaxPageSize     = 500
+)
+
+// pageParams reads the page and limit query parameters. Missing or invalid
+// values fall back to the first page and DefaultPageSize, and limit is
+// capped at MaxPageSize.
+func pageParams(c *gin.Context) (page, limit, offset int) {
+    page, err := strconv.Atoi(c.Query("page"))
+    if err != nil || page < 1 {
+        page = 1
+    }
+    limit, err = strconv.Atoi(c.Query("limit"))
+    if err != nil || limit < 1 {
+        limit = DefaultPageSize
+    }
+    if limit > MaxPageSize {
+        limit = MaxPageSize
+    }
+    return page, limit, (page - 1) * limit
+}
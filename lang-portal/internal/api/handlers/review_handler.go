@@ -0,0 +1,44 @@
+package handlers
+
+import (
+    "github.com/gin-gonic/gin"
+    "net/http"
+    "strconv"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/service"
+)
+
+type ReviewHandler struct {
+    scheduler *service.SchedulerService
+}
+
+func NewReviewHandler(scheduler *service.SchedulerService) *ReviewHandler {
+    return &ReviewHandler{scheduler: scheduler}
+}
+
+// GetDueWords godoc
+// @Summary     Get due words
+// @Description Get the next words due for review, most overdue first, then never reviewed words
+// @Tags        reviews
+// @Accept      json
+// @Produce     json
+// @Param       limit query    int  false  "Maximum number of words (1-100)"
+// @Success     200  {array}   models.DueWord
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /reviews/due [get]
+func (h *ReviewHandler) GetDueWords(c *gin.Context) {
+    limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+    if err != nil || limit < 1 || limit > 100 {
+        c.Error(repository.Invalid("limit must be between 1 and 100", map[string]string{"limit": "must be between 1 and 100"}))
+        return
+    }
+
+    words, err := h.scheduler.DueWords(c.Request.Context(), limit)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": words})
+}
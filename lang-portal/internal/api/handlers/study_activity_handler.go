@@ -0,0 +1,206 @@
+package handlers
+
+import (
+    "github.com/gin-gonic/gin"
+    "net/http"
+    "net/url"
+    "strconv"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/metrics"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "math"
+)
+
+type StudyActivityHandler struct {
+    repo     *repository.StudyActivityRepository
+    sessions repository.SessionStore
+}
+
+func NewStudyActivityHandler(repo *repository.StudyActivityRepository, sessions repository.SessionStore) *StudyActivityHandler {
+    return &StudyActivityHandler{repo: repo, sessions: sessions}
+}
+
+// GetStudyActivities godoc
+// @Summary     Get study activities list
+// @Description Get paginated list of study activities available on the launchpad
+// @Tags        study_activities
+// @Accept      json
+// @Produce     json
+// @Param       page  query    int  false  "Page number"
+// @Param       limit query    int  false  "Items per page"
+// @Success     200  {object}  models.PaginatedResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /study_activities [get]
+func (h *StudyActivityHandler) GetStudyActivities(c *gin.Context) {
+    page, limit, offset := pageParams(c)
+
+    activities, err := h.repo.GetActivities(c.Request.Context(), limit, offset)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalItems, err := h.repo.GetActivitiesCount(c.Request.Context())
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+    response := models.PaginatedResponse{
+        Data: activities,
+        Pagination: models.PaginationMeta{
+            CurrentPage:  page,
+            TotalPages:   totalPages,
+            TotalItems:   totalItems,
+            ItemsPerPage: limit,
+        },
+    }
+
+    c.JSON(http.StatusOK, response)
+}
+
+// GetStudyActivity godoc
+// @Summary     Get study activity
+// @Description Get a single study activity
+// @Tags        study_activities
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Study activity ID"
+// @Success     200  {object}  models.StudyActivityResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /study_activities/{id} [get]
+func (h *StudyActivityHandler) GetStudyActivity(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("study activity"))
+        return
+    }
+
+    activity, err := h.repo.GetActivity(c.Request.Context(), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": activity})
+}
+
+// GetStudyActivitySessions godoc
+// @Summary     Get study activity sessions
+// @Description Get paginated list of study sessions started from an activity
+// @Tags        study_activities
+// @Accept      json
+// @Produce     json
+// @Param       id    path     int  true   "Study activity ID"
+// @Param       page  query    int  false  "Page number"
+// @Param       limit query    int  false  "Items per page"
+// @Success     200  {object}  models.PaginatedResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /study_activities/{id}/study_sessions [get]
+func (h *StudyActivityHandler) GetStudyActivitySessions(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("study activity"))
+        return
+    }
+
+    if _, err := h.repo.GetActivity(c.Request.Context(), id); err != nil {
+        c.Error(err)
+        return
+    }
+
+    page, limit, offset := pageParams(c)
+
+    sessions, err := h.sessions.GetActivitySessions(c.Request.Context(), id, limit, offset)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalItems, err := h.sessions.GetActivitySessionsCount(c.Request.Context(), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+    response := models.PaginatedResponse{
+        Data: sessions,
+        Pagination: models.PaginationMeta{
+            CurrentPage:  page,
+            TotalPages:   totalPages,
+            TotalItems:   totalItems,
+            ItemsPerPage: limit,
+        },
+    }
+
+    c.JSON(http.StatusOK, response)
+}
+
+// LaunchStudyActivity godoc
+// @Summary     Launch study activity
+// @Description Start a study session for a group and return the activity's launch URL
+// @Tags        study_activities
+// @Accept      json
+// @Produce     json
+// @Param       request body      models.CreateStudySessionRequest  true  "Group and activity to launch"
+// @Success     201  {object}  models.StudyActivityLaunchResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /study_activities [post]
+func (h *StudyActivityHandler) LaunchStudyActivity(c *gin.Context) {
+    var req models.CreateStudySessionRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.Error(bindError(err))
+        return
+    }
+
+    activity, err := h.repo.GetActivity(c.Request.Context(), req.StudyActivityID)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    session, err := h.sessions.CreateSession(c.Request.Context(), &req)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    metrics.SessionStarted()
+
+    launchURL, err := buildLaunchURL(activity.LaunchURL, session)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{"data": models.StudyActivityLaunch{
+        ID:              session.ID,
+        GroupID:         session.GroupID,
+        StudyActivityID: session.StudyActivityID,
+        LaunchURL:       launchURL,
+    }})
+}
+
+// buildLaunchURL appends the group and session ids to an activity's launch
+// URL so the frontend app knows which words to load and where to report.
+func buildLaunchURL(base string, session *models.StudySession) (string, error) {
+    u, err := url.Parse(base)
+    if err != nil {
+        return "", err
+    }
+
+    q := u.Query()
+    q.Set("group_id", strconv.FormatInt(session.GroupID, 10))
+    q.Set("study_session_id", strconv.FormatInt(session.ID, 10))
+    u.RawQuery = q.Encode()
+    return u.String(), nil
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+    "context"
+    "github.com/gin-gonic/gin"
+    "net/http"
+    "strconv"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/metrics"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/service"
+    "math"
+)
+
+type StudySessionHandler struct {
+    repo      repository.SessionStore
+    store     repository.UnitOfWork
+    scheduler *service.SchedulerService
+}
+
+// NewStudySessionHandler serves sessions from repo. Writes spanning several
+// tables, like a review and the schedule it advances, go through store.
+func NewStudySessionHandler(repo repository.SessionStore, store repository.UnitOfWork, scheduler *service.SchedulerService) *StudySessionHandler {
+    return &StudySessionHandler{repo: repo, store: store, scheduler: scheduler}
+}
+
+// GetStudySessions godoc
+// @Summary     Get study sessions list
+// @Description Get paginated list of study sessions, most recent first
+// @Tags        study_sessions
+// @Accept      json
+// @Produce     json
+// @Param       page  query    int  false  "Page number"
+// @Param       limit query    int  false  "Items per page"
+// @Success     200  {object}  models.PaginatedResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /study_sessions [get]
+func (h *StudySessionHandler) GetStudySessions(c *gin.Context) {
+    page, limit, offset := pageParams(c)
+
+    sessions, err := h.repo.GetSessions(c.Request.Context(), limit, offset)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalItems, err := h.repo.GetSessionsCount(c.Request.Context())
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+    response := models.PaginatedResponse{
+        Data: sessions,
+        Pagination: models.PaginationMeta{
+            CurrentPage:  page,
+            TotalPages:   totalPages,
+            TotalItems:   totalItems,
+            ItemsPerPage: limit,
+        },
+    }
+
+    c.JSON(http.StatusOK, response)
+}
+
+// GetStudySession godoc
+// @Summary     Get study session
+// @Description Get a single study session with its review totals
+// @Tags        study_sessions
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Study session ID"
+// @Success     200  {object}  models.StudySessionResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /study_sessions/{id} [get]
+func (h *StudySessionHandler) GetStudySession(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("study session"))
+        return
+    }
+
+    session, err := h.repo.GetSession(c.Request.Context(), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": session})
+}
+
+// CreateStudySession godoc
+// @Summary     Create study session
+// @Description Start a new study session for a group and study activity.
+// @Description Answers already given, such as by an activity that ran offline, can be sent in reviews: the session, the answers and the rescheduling of their words are saved in one transaction, and if any of them fails nothing is saved.
+// @Tags        study_sessions
+// @Accept      json
+// @Produce     json
+// @Param       session body      models.RecordStudySessionRequest  true  "Session to start"
+// @Success     201  {object}  models.StudySessionResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /study_sessions [post]
+func (h *StudySessionHandler) CreateStudySession(c *gin.Context) {
+    var req models.RecordStudySessionRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.Error(bindError(err))
+        return
+    }
+
+    ctx := c.Request.Context()
+    var session *models.StudySession
+    err := h.store.WithTx(ctx, func(repos repository.Repos) error {
+        created, err := repos.Sessions.CreateSession(ctx, &req.CreateStudySessionRequest)
+        if err != nil {
+            return err
+        }
+        for _, review := range req.Reviews {
+            if _, err := h.recordReview(ctx, repos, created.ID, review.WordID, *review.Correct); err != nil {
+                return err
+            }
+        }
+        session, err = repos.Sessions.GetSession(ctx, created.ID)
+        return err
+    })
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    metrics.SessionStarted()
+    for _, review := range req.Reviews {
+        metrics.ReviewRecorded(*review.Correct)
+    }
+
+    c.JSON(http.StatusCreated, gin.H{"data": session})
+}
+
+// GetStudySessionWords godoc
+// @Summary     Get study session words
+// @Description Get paginated list of words reviewed in a session with correct/wrong counts
+// @Tags        study_sessions
+// @Accept      json
+// @Produce     json
+// @Param       id    path     int  true   "Study session ID"
+// @Param       page  query    int  false  "Page number"
+// @Param       limit query    int  false  "Items per page"
+// @Success     200  {object}  models.PaginatedResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /study_sessions/{id}/words [get]
+func (h *StudySessionHandler) GetStudySessionWords(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("study session"))
+        return
+    }
+
+    if _, err := h.repo.GetSession(c.Request.Context(), id); err != nil {
+        c.Error(err)
+        return
+    }
+
+    page, limit, offset := pageParams(c)
+
+    words, err := h.repo.GetSessionWords(c.Request.Context(), id, limit, offset)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalItems, err := h.repo.GetSessionWordsCount(c.Request.Context(), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+    response := models.PaginatedResponse{
+        Data: words,
+        Pagination: models.PaginationMeta{
+            CurrentPage:  page,
+            TotalPages:   totalPages,
+            TotalItems:   totalItems,
+            ItemsPerPage: limit,
+        },
+    }
+
+    c.JSON(http.StatusOK, response)
+}
+
+// ReviewWord godoc
+// @Summary     Review word
+// @Description Record whether a word was answered correctly during a study session and reschedule it
+// @Tags        study_sessions
+// @Accept      json
+// @Produce     json
+// @Param       id      path      int                   true  "Study session ID"
+// @Param       word_id path      int                   true  "Word ID"
+// @Param       review  body      models.ReviewRequest  true  "Review result"
+// @Success     201  {object}  models.WordReviewResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /study_sessions/{id}/words/{word_id}/review [post]
+func (h *StudySessionHandler) ReviewWord(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("study session"))
+        return
+    }
+
+    wordID, err := strconv.ParseInt(c.Param("word_id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("word"))
+        return
+    }
+
+    var req models.ReviewRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.Error(bindError(err))
+        return
+    }
+
+    ctx := c.Request.Context()
+    var item *models.WordReviewItem
+    err = h.store.WithTx(ctx, func(repos repository.Repos) error {
+        item, err = h.recordReview(ctx, repos, id, wordID, *req.Correct)
+        return err
+    })
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    metrics.ReviewRecorded(item.Correct)
+
+    c.JSON(http.StatusCreated, gin.H{"data": item})
+}
+
+// recordReview saves an answer and advances the schedule of its word, both
+// through repos so they share the caller's transaction.
+func (h *StudySessionHandler) recordReview(ctx context.Context, repos repository.Repos, sessionID, wordID int64, correct bool) (*models.WordReviewItem, error) {
+    item, err := repos.Sessions.CreateReview(ctx, sessionID, wordID, correct)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := h.scheduler.With(repos.Reviews).RecordReview(ctx, wordID, correct); err != nil {
+        return nil, err
+    }
+    return item, nil
+}
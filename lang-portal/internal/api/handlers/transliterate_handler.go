@@ -0,0 +1,64 @@
+package handlers
+
+import (
+    "errors"
+    "fmt"
+    "github.com/gin-gonic/gin"
+    "net/http"
+    "strings"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/transliterate"
+)
+
+type TransliterateHandler struct{}
+
+func NewTransliterateHandler() *TransliterateHandler {
+    return &TransliterateHandler{}
+}
+
+// Transliterate godoc
+// @Summary     Transliterate kana
+// @Description Convert hiragana and katakana to romaji in the Hepburn, Kunrei-shiki or Nihon-shiki system. Long vowels are written out (toukyou) unless macrons is set (tōkyō). Spaces and ASCII are copied; kanji cannot be transliterated.
+// @Tags        transliterate
+// @Accept      json
+// @Produce     json
+// @Param       request body      models.TransliterateRequest  true  "Kana text"
+// @Success     200  {object}  models.TransliterateResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Router      /transliterate [post]
+func (h *TransliterateHandler) Transliterate(c *gin.Context) {
+    var req models.TransliterateRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.Error(bindError(err))
+        return
+    }
+
+    system, err := transliterate.ParseSystem(req.System)
+    if err != nil {
+        names := make([]string, len(transliterate.Systems))
+        for i, s := range transliterate.Systems {
+            names[i] = string(s)
+        }
+        c.Error(repository.Invalid(err.Error(), map[string]string{"system": "must be one of " + strings.Join(names, ", ")}))
+        return
+    }
+
+    romaji, err := transliterate.ToRomaji(req.Text, transliterate.Options{System: system, Macrons: req.Macrons})
+    var notKana *transliterate.Error
+    if errors.As(err, &notKana) {
+        c.Error(repository.Invalid(err.Error(), map[string]string{"text": fmt.Sprintf("must be kana, found %q", notKana.Rune)}))
+        return
+    }
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": models.Transliteration{
+        Text:    req.Text,
+        System:  string(system),
+        Macrons: req.Macrons,
+        Romaji:  romaji,
+    }})
+}
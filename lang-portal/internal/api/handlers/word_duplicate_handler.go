@@ -0,0 +1,72 @@
+package handlers
+
+import (
+    "net/http"
+    "strconv"
+    "github.com/gin-gonic/gin"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+)
+
+// GetDuplicates godoc
+// @Summary     Find duplicate words
+// @Description List the sets of words whose japanese and english are the same once normalized: full-width and half-width forms folded, katakana read as hiragana, case and spacing ignored. Sets are ordered by the normalized text and words by id.
+// @Tags        words
+// @Produce     json
+// @Success     200  {object}  models.DuplicatesResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /words/duplicates [get]
+func (h *WordHandler) GetDuplicates(c *gin.Context) {
+    sets, err := h.repo.FindDuplicates(c.Request.Context())
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": sets})
+}
+
+// MergeWords godoc
+// @Summary     Merge words
+// @Description Fold the listed words into this one: their review history and group memberships move to it and they are deleted. The word keeps its text, parts and review schedule, taking the schedule of the first listed word that has one if it has none.
+// @Tags        words
+// @Accept      json
+// @Produce     json
+// @Param       id      path      int                      true  "ID of the word to keep"
+// @Param       request body      models.WordMergeRequest  true  "IDs of the words to merge into it"
+// @Success     200  {object}  models.WordDetailResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /words/{id}/merge [post]
+func (h *WordHandler) MergeWords(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("word"))
+        return
+    }
+
+    var req models.WordMergeRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.Error(bindError(err))
+        return
+    }
+
+    if len(req.WordIDs) == 0 {
+        c.Error(repository.Invalid("word_ids is required", map[string]string{"word_ids": "required field"}))
+        return
+    }
+
+    if err := h.repo.MergeWords(c.Request.Context(), id, req.WordIDs); err != nil {
+        c.Error(err)
+        return
+    }
+
+    word, err := h.repo.GetWord(c.Request.Context(), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": word})
+}
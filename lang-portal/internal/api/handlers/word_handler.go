@@ -6,6 +6,7 @@ import (
     "strconv"
     "github.com/karl247ai/lang-portal/internal/repository"
     "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/validator"
     "math"
 )
 
@@ -16,36 +17,65 @@ import (
 // @BasePath       /api/v1
 
 type WordHandler struct {
-    repo *repository.WordRepository
+    repo repository.WordStore
 }
 
-func NewWordHandler(repo *repository.WordRepository) *WordHandler {
+func NewWordHandler(repo repository.WordStore) *WordHandler {
     return &WordHandler{repo: repo}
 }
 
 // GetWords godoc
 // @Summary     Get words list
-// @Description Get paginated list of words
+// @Description Get paginated list of words, optionally searched and filtered. Search results are ordered by relevance unless sort is given.
+// @Description Every page but the last returns pagination.next_cursor; passing it as cursor fetches the next page by keyset, which stays consistent while words are inserted. In cursor mode page is ignored, current_page is 0, and the sort stored in the cursor applies.
 // @Tags        words
 // @Accept      json
 // @Produce     json
-// @Param       page  query    int  false  "Page number"
-// @Param       limit query    int  false  "Items per page"
+// @Param       page     query    int     false  "Page number"
+// @Param       limit    query    int     false  "Items per page"
+// @Param       cursor   query    string  false  "Opaque cursor from pagination.next_cursor"
+// @Param       sort     query    string  false  "Sort field"  Enums(japanese, romaji, english, created_at, correct_count)
+// @Param       order    query    string  false  "Sort direction"  Enums(asc, desc)
+// @Param       q        query    string  false  "Free text matched against japanese, romaji, english and parts"
+// @Param       prefix   query    bool    false  "Match words starting with each term of q"
+// @Param       group_id query    int     false  "Only words in this group"
+// @Param       pos      query    string  false  "Only words with this parts.part_of_speech"
+// @Param       jlpt     query    string  false  "Only words with this parts.jlpt_level, 1-5 or N1-N5"
+// @Param       kanji    query    string  false  "Only words with a parts.components entry for this kanji"
+// @Param       has_examples query bool false  "Only words with (true) or without (false) parts.examples"
+// @Param       pitch_accent query int  false  "Only words with this parts.pitch_accent"
 // @Success      200  {object}  models.PaginatedResponse
+// @Failure     400  {object}  models.ErrorResponse
 // @Failure     500  {object}  models.ErrorResponse
 // @Router      /words [get]
 func (h *WordHandler) GetWords(c *gin.Context) {
-    page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-    limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-    offset := (page - 1) * limit
+    page, limit, offset := pageParams(c)
 
-    words, err := h.repo.GetWords(c.Request.Context(), limit, offset)
+    filter, err := wordFilter(c)
     if err != nil {
         c.Error(err)
         return
     }
 
-    totalItems, err := h.repo.GetWordsCount(c.Request.Context())
+    var words []models.Word
+    var nextCursor string
+    if cursor := c.Query("cursor"); cursor != "" {
+        page = 0
+        words, nextCursor, err = h.repo.GetWordsAfter(c.Request.Context(), filter, cursor, limit)
+    } else {
+        sort, sortErr := wordSort(c)
+        if sortErr != nil {
+            c.Error(sortErr)
+            return
+        }
+        words, nextCursor, err = h.repo.GetWords(c.Request.Context(), filter, sort, limit, offset)
+    }
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    totalItems, err := h.repo.GetWordsCount(c.Request.Context(), filter)
     if err != nil {
         c.Error(err)
         return
@@ -60,47 +90,84 @@ func (h *WordHandler) GetWords(c *gin.Context) {
             TotalPages:   totalPages,
             TotalItems:   totalItems,
             ItemsPerPage: limit,
+            NextCursor:   nextCursor,
         },
     }
 
     c.JSON(http.StatusOK, response)
 }
 
+// GetWord godoc
+// @Summary     Get word
+// @Description Get a single word with its review stats and the groups it belongs to
+// @Tags        words
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Word ID"
+// @Success     200  {object}  models.WordDetailResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /words/{id} [get]
+func (h *WordHandler) GetWord(c *gin.Context) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        c.Error(invalidID("word"))
+        return
+    }
+
+    word, err := h.repo.GetWord(c.Request.Context(), id)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"data": word})
+}
+
 // CreateWord godoc
 // @Summary     Create new word
-// @Description Add a new word to the vocabulary
+// @Description Add a new word to the vocabulary. When romaji is omitted it is transliterated to Hepburn from the kana reading: parts.reading, or japanese itself if written in kana.
+// @Description A word whose japanese and english match an existing word, ignoring width, katakana/hiragana, case and spacing, follows the server's duplicate policy: reject answers 409 with error.details.duplicate_id, upsert updates the existing word and answers 200, allow creates it anyway.
 // @Tags        words
 // @Accept      json
 // @Produce     json
 // @Param       word body      models.Word  true  "Word object"
+// @Success     200  {object}  models.WordResponse
 // @Success     201  {object}  models.WordResponse
 // @Failure     400  {object}  models.ErrorResponse
+// @Failure     409  {object}  models.ErrorResponse
 // @Failure     500  {object}  models.ErrorResponse
 // @Router      /words [post]
 func (h *WordHandler) CreateWord(c *gin.Context) {
     var word models.Word
     if err := c.ShouldBindJSON(&word); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        c.Error(bindError(err))
         return
     }
 
-    // Validate required fields
-    if word.Japanese == "" || word.Romaji == "" || word.English == "" {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
+    validator.FillRomaji(&word)
+    if err := validator.ValidateWord(&word); err != nil {
+        c.Error(err)
         return
     }
 
-    if err := h.repo.CreateWord(c.Request.Context(), &word); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+    created, err := h.repo.CreateWord(c.Request.Context(), &word)
+    if err != nil {
+        c.Error(err)
         return
     }
 
-    c.JSON(http.StatusCreated, gin.H{"data": word})
+    status := http.StatusCreated
+    if !created {
+        status = http.StatusOK
+    }
+    c.JSON(status, gin.H{"data": word})
 }
 
 // UpdateWord godoc
 // @Summary     Update word
-// @Description Update an existing word
+// @Description Update an existing word. When romaji is omitted it is filled in as for POST /words. Unless the duplicate policy is allow, turning the word into a duplicate of another answers 409.
 // @Tags        words
 // @Accept      json
 // @Produce     json
@@ -109,32 +176,30 @@ func (h *WordHandler) CreateWord(c *gin.Context) {
 // @Success     200  {object}  models.WordResponse
 // @Failure     400  {object}  models.ErrorResponse
 // @Failure     404  {object}  models.ErrorResponse
+// @Failure     409  {object}  models.ErrorResponse
 // @Failure     500  {object}  models.ErrorResponse
 // @Router      /words/{id} [put]
 func (h *WordHandler) UpdateWord(c *gin.Context) {
     id, err := strconv.ParseInt(c.Param("id"), 10, 64)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+        c.Error(invalidID("word"))
         return
     }
 
     var word models.Word
     if err := c.ShouldBindJSON(&word); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        c.Error(bindError(err))
         return
     }
 
+    validator.FillRomaji(&word)
     if err := validator.ValidateWord(&word); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
     if err := h.repo.UpdateWord(c.Request.Context(), id, &word); err != nil {
-        if err.Error() == "word not found" {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
@@ -156,18 +221,73 @@ func (h *WordHandler) UpdateWord(c *gin.Context) {
 func (h *WordHandler) DeleteWord(c *gin.Context) {
     id, err := strconv.ParseInt(c.Param("id"), 10, 64)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid word id"})
+        c.Error(invalidID("word"))
         return
     }
 
     if err := h.repo.DeleteWord(c.Request.Context(), id); err != nil {
-        if err.Error() == "word not found" {
-            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-            return
-        }
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        c.Error(err)
         return
     }
 
     c.Status(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+// wordFilter reads the search and filter query parameters of GetWords.
+func wordFilter(c *gin.Context) (models.WordFilter, error) {
+    filter := models.WordFilter{
+        Query:        c.Query("q"),
+        PartOfSpeech: c.Query("pos"),
+    }
+
+    if v := c.Query("prefix"); v != "" {
+        prefix, err := strconv.ParseBool(v)
+        if err != nil {
+            return filter, repository.Invalid("invalid prefix", map[string]string{"prefix": "must be true or false"})
+        }
+        filter.Prefix = prefix
+    }
+    if v := c.Query("group_id"); v != "" {
+        groupID, err := strconv.ParseInt(v, 10, 64)
+        if err != nil || groupID < 1 {
+            return filter, invalidID("group")
+        }
+        filter.GroupID = groupID
+    }
+    if v := c.Query("jlpt"); v != "" {
+        level, err := models.ParseJLPTLevel(v)
+        if err != nil || level < 1 || level > 5 {
+            return filter, repository.Invalid("invalid jlpt", map[string]string{"jlpt": "must be from 1 (N1) to 5 (N5)"})
+        }
+        filter.JLPTLevel = level
+    }
+    filter.Kanji = c.Query("kanji")
+    if v := c.Query("has_examples"); v != "" {
+        hasExamples, err := strconv.ParseBool(v)
+        if err != nil {
+            return filter, repository.Invalid("invalid has_examples", map[string]string{"has_examples": "must be true or false"})
+        }
+        filter.HasExamples = &hasExamples
+    }
+    if v := c.Query("pitch_accent"); v != "" {
+        accent, err := strconv.Atoi(v)
+        if err != nil || accent < 0 {
+            return filter, repository.Invalid("invalid pitch_accent", map[string]string{"pitch_accent": "must be a non-negative number"})
+        }
+        filter.PitchAccent = &accent
+    }
+    return filter, nil
+}
+
+// wordSort reads the sort and order query parameters of GetWords.
+func wordSort(c *gin.Context) (models.WordSort, error) {
+    sort := models.WordSort{Field: c.Query("sort")}
+    switch c.DefaultQuery("order", "asc") {
+    case "asc":
+    case "desc":
+        sort.Desc = true
+    default:
+        return sort, repository.Invalid("invalid order", map[string]string{"order": "must be asc or desc"})
+    }
+    return sort, nil
+}
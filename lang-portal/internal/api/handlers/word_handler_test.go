@@ -1,32 +1,120 @@
 package handlers
 
 import (
+    "context"
+    "errors"
+    "io"
+    "log/slog"
     "testing"
     "net/http"
     "net/http/httptest"
     "encoding/json"
     "github.com/gin-gonic/gin"
     "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/karl247ai/lang-portal/internal/middleware"
     "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
     "bytes"
 )
 
+// fakeWordStore keeps words in memory, enough of a repository.WordStore for
+// the word handlers.
+type fakeWordStore struct {
+    repository.WordStore
+    words  map[int64]models.Word
+    nextID int64
+    // eachErr, when set, fails EachWord after the first word
+    eachErr error
+}
+
+func newFakeWordStore(words ...models.Word) *fakeWordStore {
+    s := &fakeWordStore{words: map[int64]models.Word{}}
+    for _, w := range words {
+        s.nextID++
+        w.ID = s.nextID
+        s.words[w.ID] = w
+    }
+    return s
+}
+
+func (s *fakeWordStore) GetWords(ctx context.Context, filter models.WordFilter, sort models.WordSort, limit, offset int) ([]models.Word, string, error) {
+    words := []models.Word{}
+    for id := int64(1); id <= s.nextID; id++ {
+        if w, ok := s.words[id]; ok {
+            words = append(words, w)
+        }
+    }
+    if offset > len(words) {
+        offset = len(words)
+    }
+    words = words[offset:]
+    if len(words) > limit {
+        words = words[:limit]
+    }
+    return words, "", nil
+}
+
+func (s *fakeWordStore) GetWordsCount(ctx context.Context, filter models.WordFilter) (int64, error) {
+    return int64(len(s.words)), nil
+}
+
+func (s *fakeWordStore) EachWord(ctx context.Context, groupID int64, fn func(models.Word) error) error {
+    for id := int64(1); id <= s.nextID; id++ {
+        w, ok := s.words[id]
+        if !ok {
+            continue
+        }
+        if err := fn(w); err != nil {
+            return err
+        }
+        if s.eachErr != nil {
+            return s.eachErr
+        }
+    }
+    return nil
+}
+
+func (s *fakeWordStore) CreateWord(ctx context.Context, word *models.Word) (bool, error) {
+    s.nextID++
+    word.ID = s.nextID
+    s.words[word.ID] = *word
+    return true, nil
+}
+
+func (s *fakeWordStore) UpdateWord(ctx context.Context, id int64, word *models.Word) error {
+    if _, ok := s.words[id]; !ok {
+        return repository.NotFound("word")
+    }
+    word.ID = id
+    s.words[id] = *word
+    return nil
+}
+
+func (s *fakeWordStore) DeleteWord(ctx context.Context, id int64) error {
+    if _, ok := s.words[id]; !ok {
+        return repository.NotFound("word")
+    }
+    delete(s.words, id)
+    return nil
+}
+
 func setupTestRouter(t *testing.T) (*gin.Engine, *WordHandler) {
     gin.SetMode(gin.TestMode)
-    r := gin.Default()
-    
-    db := setupTestDB(t)
-    repo := repository.NewWordRepository(db)
+    r := gin.New()
+    r.Use(middleware.ErrorHandler())
+
+    repo := newFakeWordStore(models.Word{Japanese: "こんにちは", Romaji: "konnichiwa", English: "hello"})
     handler := NewWordHandler(repo)
-    
+
     return r, handler
 }
 
 type ResponseData struct {
     Data       []models.Word `json:"data"`
     Pagination struct {
-        Page  int `json:"page"`
-        Limit int `json:"limit"`
+        Page  int `json:"current_page"`
+        Limit int `json:"items_per_page"`
     } `json:"pagination"`
 }
 
@@ -50,8 +138,8 @@ func TestWordHandler_GetWords(t *testing.T) {
             wantCount:  1,
         },
         {
-            name:       "invalid_page_number",
-            url:        "/api/v1/words?page=invalid",
+            name:       "invalid_order",
+            url:        "/api/v1/words?order=sideways",
             wantStatus: http.StatusBadRequest,
             wantCount:  0,
         },
@@ -91,7 +179,7 @@ func TestWordHandler_CreateWord(t *testing.T) {
                 "japanese": "猫",
                 "romaji":   "neko",
                 "english":  "cat",
-                "parts":    json.RawMessage(`{"type": "noun"}`),
+                "parts":    json.RawMessage(`{"part_of_speech": "noun"}`),
             },
             wantStatus: http.StatusCreated,
         },
@@ -116,7 +204,7 @@ func TestWordHandler_CreateWord(t *testing.T) {
             r.ServeHTTP(w, req)
 
             assert.Equal(t, tt.wantStatus, w.Code)
-            
+
             if tt.wantStatus == http.StatusCreated {
                 var response struct {
                     Data models.Word `json:"data"`
@@ -151,10 +239,19 @@ func TestWordHandler_UpdateWord(t *testing.T) {
             name:   "invalid_word_id",
             wordID: "999",
             payload: map[string]interface{}{
-                "japanese": "犬",
+                "japanese": "いぬ",
+                "english":  "dog",
             },
             wantStatus: http.StatusNotFound,
         },
+        {
+            name:   "missing_required_fields",
+            wordID: "1",
+            payload: map[string]interface{}{
+                "japanese": "犬",
+            },
+            wantStatus: http.StatusBadRequest,
+        },
     }
 
     r, handler := setupTestRouter(t)
@@ -208,4 +305,30 @@ func TestWordHandler_DeleteWord(t *testing.T) {
             assert.Equal(t, tt.wantStatus, w.Code)
         })
     }
-}
\ No newline at end of file
+}
+
+func TestWordHandler_ExportWordsFailsMidway(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    var logs bytes.Buffer
+    r := gin.New()
+    r.Use(middleware.RequestID(slog.New(slog.NewTextHandler(&logs, nil))))
+    r.Use(middleware.ErrorHandler())
+
+    repo := newFakeWordStore(models.Word{Japanese: "猫", Romaji: "neko", English: "cat"}, models.Word{Japanese: "犬", Romaji: "inu", English: "dog"})
+    repo.eachErr = errors.New("disk I/O error")
+    r.GET("/api/v1/words/export", NewWordHandler(repo).ExportWords)
+
+    srv := httptest.NewServer(r)
+    defer srv.Close()
+
+    resp, err := http.Get(srv.URL + "/api/v1/words/export")
+    require.NoError(t, err)
+    defer resp.Body.Close()
+    assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+    // The connection is dropped rather than ending a partial file cleanly
+    _, err = io.ReadAll(resp.Body)
+    assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+    assert.Contains(t, logs.String(), "word export failed after the response started")
+    assert.Contains(t, logs.String(), "disk I/O error")
+}
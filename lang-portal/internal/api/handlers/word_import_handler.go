@@ -0,0 +1,266 @@
+package handlers
+
+import (
+    "errors"
+    "fmt"
+    "net/http"
+    "strconv"
+    "strings"
+    "github.com/gin-gonic/gin"
+    "github.com/karl247ai/lang-portal/internal/logging"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/validator"
+    "github.com/karl247ai/lang-portal/internal/wordio"
+)
+
+// MaxImportSize caps the size of an uploaded vocabulary file.
+const MaxImportSize = 10 << 20
+
+// ImportWords godoc
+// @Summary     Import words
+// @Description Create words in bulk from a CSV file (header japanese,romaji,english[,parts]), a JSON array of words, an Anki plain text notes export (.txt) or an Anki package (.apkg). Missing romaji is transliterated from kana as for POST /words.
+// @Description The import runs in a single transaction: if any row is invalid nothing is written and error.details lists the problems per row, keyed rows[N].field where N is the line (CSV, text) or position (JSON, Anki package) of the row.
+// @Description Rows duplicating an existing word, or an earlier row, follow the server's duplicate policy: with reject the import fails with 409 and error.details keyed rows[N], with upsert the existing word is updated and counted in updated.
+// @Tags        words
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       file     formData  file    true   "Vocabulary file, at most 10 MB"
+// @Param       format   formData  string  false  "File format, taken from the file extension when omitted"  Enums(csv, json, txt, apkg)
+// @Param       group_id formData  int     false  "Add the imported words to this group"
+// @Success     201  {object}  models.WordImportResponse
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     409  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /words/import [post]
+func (h *WordHandler) ImportWords(c *gin.Context) {
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxImportSize)
+
+    header, err := c.FormFile("file")
+    if err != nil {
+        var tooLarge *http.MaxBytesError
+        if errors.As(err, &tooLarge) || strings.Contains(err.Error(), "request body too large") {
+            c.Error(repository.Invalid("file is too large", map[string]string{"file": "must be at most 10 MB"}))
+            return
+        }
+        c.Error(repository.Invalid("file is required", map[string]string{"file": "required field"}))
+        return
+    }
+
+    var format wordio.Format
+    if name := c.PostForm("format"); name != "" {
+        format, err = wordio.ParseFormat(name)
+    } else {
+        format, err = wordio.FormatFromFilename(header.Filename)
+    }
+    if err != nil {
+        c.Error(repository.Invalid(err.Error(), map[string]string{"format": "must be one of " + formatNames()}))
+        return
+    }
+
+    var groupID int64
+    if v := c.PostForm("group_id"); v != "" {
+        groupID, err = strconv.ParseInt(v, 10, 64)
+        if err != nil || groupID < 1 {
+            c.Error(invalidID("group"))
+            return
+        }
+    }
+
+    file, err := header.Open()
+    if err != nil {
+        c.Error(err)
+        return
+    }
+    defer file.Close()
+
+    rows, err := wordio.Decode(format, file, header.Size)
+    if err != nil {
+        c.Error(repository.Invalid("cannot read "+string(format)+" file: "+err.Error(), map[string]string{"file": err.Error()}))
+        return
+    }
+    if len(rows) == 0 {
+        c.Error(repository.Invalid("file contains no words", map[string]string{"file": "no words found"}))
+        return
+    }
+
+    words, err := validateRows(rows)
+    if err != nil {
+        c.Error(err)
+        return
+    }
+
+    updated, err := h.repo.CreateWords(c.Request.Context(), words, groupID)
+    if err != nil {
+        c.Error(rowConflicts(err, rows))
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{"data": models.WordImport{
+        Format:   string(format),
+        Imported: len(words),
+        Updated:  updated,
+        GroupID:  groupID,
+    }})
+}
+
+// ExportWords godoc
+// @Summary     Export words
+// @Description Download the vocabulary, or the words of one group, as CSV, a JSON array, Anki plain text notes or an Anki package. The file is streamed in id order.
+// @Tags        words
+// @Produce     text/csv
+// @Produce     json
+// @Produce     text/plain
+// @Produce     application/octet-stream
+// @Param       format   query  string  false  "File format"  Enums(csv, json, txt, apkg)  default(csv)
+// @Param       group_id query  int     false  "Only words in this group"
+// @Success     200  {file}    file
+// @Failure     400  {object}  models.ErrorResponse
+// @Failure     404  {object}  models.ErrorResponse
+// @Failure     500  {object}  models.ErrorResponse
+// @Router      /words/export [get]
+func (h *WordHandler) ExportWords(c *gin.Context) {
+    format, err := wordio.ParseFormat(c.DefaultQuery("format", string(wordio.CSV)))
+    if err != nil {
+        c.Error(repository.Invalid(err.Error(), map[string]string{"format": "must be one of " + formatNames()}))
+        return
+    }
+
+    var groupID int64
+    if v := c.Query("group_id"); v != "" {
+        groupID, err = strconv.ParseInt(v, 10, 64)
+        if err != nil || groupID < 1 {
+            c.Error(invalidID("group"))
+            return
+        }
+    }
+
+    // The encoder is created with the first word so a missing group can
+    // still be reported before the response is committed
+    var enc wordio.Encoder
+    start := func() error {
+        filename := "words." + string(format)
+        if groupID != 0 {
+            filename = fmt.Sprintf("group-%d-words.%s", groupID, format)
+        }
+        c.Header("Content-Type", format.ContentType())
+        c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+        c.Status(http.StatusOK)
+
+        var err error
+        enc, err = wordio.NewEncoder(format, c.Writer)
+        return err
+    }
+
+    err = h.repo.EachWord(c.Request.Context(), groupID, func(w models.Word) error {
+        if enc == nil {
+            if err := start(); err != nil {
+                return err
+            }
+        }
+        return enc.Encode(w)
+    })
+    if err == nil && enc == nil {
+        err = start()
+    }
+    if err == nil {
+        err = enc.Close()
+    }
+    if err != nil {
+        c.Error(err)
+        if enc != nil {
+            // The download has begun, so ErrorHandler cannot report the
+            // error; log it and drop the connection so the client does not
+            // take the partial file for a complete one
+            logging.FromContext(c.Request.Context()).Error("word export failed after the response started",
+                "format", string(format), "group_id", groupID, "error", err)
+            abortConnection(c)
+        }
+    }
+}
+
+// abortConnection sends what was written so far and closes the client
+// connection without ending the response, so the client sees it cut short.
+// Writers that cannot be hijacked, such as test recorders, are left alone.
+func abortConnection(c *gin.Context) {
+    c.Writer.Flush()
+    var w http.ResponseWriter = c.Writer
+    if u, ok := w.(interface{ Unwrap() http.ResponseWriter }); ok {
+        w = u.Unwrap()
+    }
+    if conn, _, err := http.NewResponseController(w).Hijack(); err == nil {
+        conn.Close()
+    }
+}
+
+// validateRows checks every decoded row and returns the words, or a single
+// validation error describing each failing field of each row.
+func validateRows(rows []wordio.Row) ([]models.Word, error) {
+    words := make([]models.Word, len(rows))
+    fields := map[string]string{}
+    for i, row := range rows {
+        words[i] = row.Word
+        validator.FillRomaji(&words[i])
+        prefix := fmt.Sprintf("rows[%d].", row.Line)
+
+        var invalid *repository.Error
+        if err := validator.ValidateWord(&words[i]); errors.As(err, &invalid) {
+            for field, problem := range invalid.Details {
+                fields[prefix+field] = problem
+            }
+        } else if err != nil {
+            return nil, err
+        }
+        for field, problem := range row.Invalid {
+            fields[prefix+field] = problem
+        }
+    }
+
+    if len(fields) > 0 {
+        return nil, repository.Invalid(fmt.Sprintf("%d of %d rows are invalid, nothing was imported", invalidRows(fields), len(rows)), fields)
+    }
+    return words, nil
+}
+
+// rowConflicts rewrites the words[N] keys of a duplicate conflict from
+// CreateWords into the rows[N] keys used by the rest of the import errors.
+func rowConflicts(err error, rows []wordio.Row) error {
+    var conflict *repository.Error
+    if !errors.As(err, &conflict) || !errors.Is(err, repository.ErrConflict) {
+        return err
+    }
+
+    pairs := make([]string, 0, 2*len(rows))
+    for i, row := range rows {
+        pairs = append(pairs, fmt.Sprintf("words[%d]", i), fmt.Sprintf("rows[%d]", row.Line))
+    }
+    rename := strings.NewReplacer(pairs...)
+
+    details := make(map[string]string, len(conflict.Details))
+    for key, problem := range conflict.Details {
+        details[rename.Replace(key)] = rename.Replace(problem)
+    }
+    return &repository.Error{
+        Kind:    repository.ErrConflict,
+        Message: fmt.Sprintf("%d of %d rows are duplicates, nothing was imported", len(details), len(rows)),
+        Details: details,
+    }
+}
+
+// invalidRows counts the distinct rows named in validation field keys.
+func invalidRows(fields map[string]string) int {
+    rows := map[string]bool{}
+    for key := range fields {
+        rows[key[:strings.Index(key, ".")]] = true
+    }
+    return len(rows)
+}
+
+func formatNames() string {
+    names := make([]string, len(wordio.Formats))
+    for i, f := range wordio.Formats {
+        names[i] = string(f)
+    }
+    return strings.Join(names, ", ")
+}
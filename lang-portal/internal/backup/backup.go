@@ -0,0 +1,364 @@
+// Package backup writes consistent snapshots of the live SQLite database to
+// a backup folder with the online backup API, prunes old snapshots and
+// restores them.
+package backup
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "log/slog"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+    sqlite3 "github.com/mattn/go-sqlite3"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+// timeFormat orders backup file names chronologically.
+const timeFormat = "20060102-150405"
+
+// ScheduledLabel names the snapshots taken by Run.
+const ScheduledLabel = "scheduled"
+
+var (
+    validLabel = regexp.MustCompile(`^[a-z0-9-]+$`)
+    // langportal-<label>-<time>[.<ms>].db
+    fileName = regexp.MustCompile(`^langportal-([a-z0-9-]+)-(\d{8}-\d{6})(?:\.(\d{3}))?\.db$`)
+)
+
+// Manager creates, lists and restores backups of db in dir. Keep limits
+// the snapshots kept per label, the newest first; 0 keeps every snapshot.
+// Snapshots and restores run one at a time.
+type Manager struct {
+    db   *sql.DB
+    dir  string
+    keep int
+    now  func() time.Time
+    mu   sync.Mutex
+}
+
+func NewManager(db *sql.DB, dir string, keep int) *Manager {
+    return &Manager{db: db, dir: dir, keep: keep, now: time.Now}
+}
+
+// Snapshot copies the database to langportal-<label>-<time>.db while the
+// server keeps running, then prunes older snapshots of the same label. The
+// copy is written to a temporary file and only renamed into place once it
+// passes an integrity check.
+func (m *Manager) Snapshot(ctx context.Context, label string) (*models.Backup, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    return m.snapshot(ctx, label)
+}
+
+// snapshot implements Snapshot; the caller holds m.mu.
+func (m *Manager) snapshot(ctx context.Context, label string) (*models.Backup, error) {
+    if !validLabel.MatchString(label) {
+        return nil, fmt.Errorf("invalid backup label %q", label)
+    }
+    if err := os.MkdirAll(m.dir, 0o755); err != nil {
+        return nil, fmt.Errorf("create backup folder: %w", err)
+    }
+
+    created := m.now().UTC()
+    name := fmt.Sprintf("langportal-%s-%s.db", label, created.Format(timeFormat))
+    if exists(filepath.Join(m.dir, name)) {
+        // Two snapshots within a second keep distinct names, moving to the
+        // next free millisecond so an existing snapshot is never replaced
+        for {
+            name = fmt.Sprintf("langportal-%s-%s.%03d.db", label, created.Format(timeFormat), created.Nanosecond()/1e6)
+            if !exists(filepath.Join(m.dir, name)) {
+                break
+            }
+            created = created.Add(time.Millisecond)
+        }
+    }
+    path := filepath.Join(m.dir, name)
+
+    tmp := path + ".tmp"
+    if err := m.writeSnapshot(ctx, tmp); err != nil {
+        os.Remove(tmp)
+        return nil, err
+    }
+    if err := os.Rename(tmp, path); err != nil {
+        os.Remove(tmp)
+        return nil, err
+    }
+
+    info, err := os.Stat(path)
+    if err != nil {
+        return nil, err
+    }
+    if err := m.prune(label); err != nil {
+        return nil, err
+    }
+    return &models.Backup{
+        Name:      name,
+        Label:     label,
+        SizeBytes: info.Size(),
+        CreatedAt: created.Format(time.RFC3339),
+    }, nil
+}
+
+func (m *Manager) writeSnapshot(ctx context.Context, path string) error {
+    dst, err := sql.Open("sqlite3", path)
+    if err != nil {
+        return err
+    }
+    defer dst.Close()
+
+    if err := copyDatabase(ctx, dst, m.db); err != nil {
+        return fmt.Errorf("back up database: %w", err)
+    }
+    // The copy takes the journal mode of the live database; a snapshot is
+    // a single file, without a write-ahead log next to it
+    if _, err := dst.ExecContext(ctx, "PRAGMA journal_mode=DELETE"); err != nil {
+        return err
+    }
+    if err := integrityCheck(ctx, dst); err != nil {
+        return fmt.Errorf("backup failed integrity check: %w", err)
+    }
+    return dst.Close()
+}
+
+// List returns the backups in the backup folder, newest first. Files not
+// named like a snapshot are ignored.
+func (m *Manager) List() ([]models.Backup, error) {
+    entries, err := os.ReadDir(m.dir)
+    if os.IsNotExist(err) {
+        return []models.Backup{}, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    backups := []models.Backup{}
+    times := map[string]time.Time{}
+    for _, entry := range entries {
+        match := fileName.FindStringSubmatch(entry.Name())
+        if entry.IsDir() || match == nil {
+            continue
+        }
+        created, err := time.Parse(timeFormat, match[2])
+        if err != nil {
+            continue
+        }
+        // Snapshots taken within the same second carry milliseconds
+        if match[3] != "" {
+            ms, _ := strconv.Atoi(match[3])
+            created = created.Add(time.Duration(ms) * time.Millisecond)
+        }
+        times[entry.Name()] = created
+        info, err := entry.Info()
+        if err != nil {
+            return nil, err
+        }
+        backups = append(backups, models.Backup{
+            Name:      entry.Name(),
+            Label:     match[1],
+            SizeBytes: info.Size(),
+            CreatedAt: created.Format(time.RFC3339),
+        })
+    }
+
+    // Names do not sort by time: langportal-x-<time>.db comes after
+    // langportal-x-<time>.123.db, taken later in the same second
+    sort.Slice(backups, func(i, j int) bool {
+        ti, tj := times[backups[i].Name], times[backups[j].Name]
+        if !ti.Equal(tj) {
+            return ti.After(tj)
+        }
+        return backups[i].Name > backups[j].Name
+    })
+    return backups, nil
+}
+
+// prune deletes the snapshots of label beyond the newest keep.
+func (m *Manager) prune(label string) error {
+    if m.keep <= 0 {
+        return nil
+    }
+    backups, err := m.List()
+    if err != nil {
+        return err
+    }
+
+    kept := 0
+    for _, b := range backups {
+        if b.Label != label {
+            continue
+        }
+        if kept < m.keep {
+            kept++
+            continue
+        }
+        if err := os.Remove(filepath.Join(m.dir, b.Name)); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// Check opens the named backup and runs SQLite's integrity check on it.
+func (m *Manager) Check(ctx context.Context, name string) error {
+    path, err := m.path(name)
+    if err != nil {
+        return err
+    }
+    src, err := openReadOnly(path)
+    if err != nil {
+        return err
+    }
+    defer src.Close()
+    return integrityCheck(ctx, src)
+}
+
+// Restore replaces the contents of the live database with the named
+// backup. The backup is checked first and the current database is saved as
+// a pre-restore snapshot. The copy goes through the online backup API, so
+// it happens under a lock on the live database and open connections see
+// either the old or the restored data, never a mix; stopping the server
+// first is still advised so it does not act on data that just vanished.
+func (m *Manager) Restore(ctx context.Context, name string) (*models.Backup, error) {
+    path, err := m.path(name)
+    if err != nil {
+        return nil, err
+    }
+    src, err := openReadOnly(path)
+    if err != nil {
+        return nil, err
+    }
+    defer src.Close()
+
+    if err := integrityCheck(ctx, src); err != nil {
+        return nil, fmt.Errorf("backup %s failed integrity check: %w", name, err)
+    }
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    saved, err := m.snapshot(ctx, "pre-restore")
+    if err != nil {
+        return nil, fmt.Errorf("save current database: %w", err)
+    }
+    if err := copyDatabase(ctx, m.db, src); err != nil {
+        return nil, fmt.Errorf("restore %s: %w", name, err)
+    }
+    return saved, nil
+}
+
+// Run takes a scheduled snapshot every interval until ctx is done. Errors
+// are logged and the next snapshot is attempted on schedule.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            b, err := m.Snapshot(ctx, ScheduledLabel)
+            if err != nil {
+                slog.Error("scheduled backup failed", "error", err)
+                continue
+            }
+            slog.Info("wrote backup", "name", b.Name, "bytes", b.SizeBytes)
+        }
+    }
+}
+
+// path resolves a backup name inside the backup folder. Only snapshot
+// names are accepted, so a name cannot point outside the folder.
+func (m *Manager) path(name string) (string, error) {
+    if !fileName.MatchString(name) {
+        return "", fmt.Errorf("invalid backup name %q", name)
+    }
+    path := filepath.Join(m.dir, name)
+    if _, err := os.Stat(path); err != nil {
+        return "", fmt.Errorf("backup %s: %w", name, err)
+    }
+    return path, nil
+}
+
+func exists(path string) bool {
+    _, err := os.Stat(path)
+    return err == nil
+}
+
+func openReadOnly(path string) (*sql.DB, error) {
+    db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+    if err != nil {
+        return nil, err
+    }
+    return db, db.Ping()
+}
+
+// copyDatabase copies the main database of src over dst page by page with
+// the SQLite online backup API.
+func copyDatabase(ctx context.Context, dst, src *sql.DB) error {
+    dstConn, err := dst.Conn(ctx)
+    if err != nil {
+        return err
+    }
+    defer dstConn.Close()
+    srcConn, err := src.Conn(ctx)
+    if err != nil {
+        return err
+    }
+    defer srcConn.Close()
+
+    return dstConn.Raw(func(dstDriver interface{}) error {
+        return srcConn.Raw(func(srcDriver interface{}) error {
+            to, ok := dstDriver.(*sqlite3.SQLiteConn)
+            from, ok2 := srcDriver.(*sqlite3.SQLiteConn)
+            if !ok || !ok2 {
+                return fmt.Errorf("backups need the sqlite3 driver")
+            }
+
+            b, err := to.Backup("main", from, "main")
+            if err != nil {
+                return err
+            }
+            // One step copies every page under a single read lock, so the
+            // copy is consistent even while the server writes
+            if _, err := b.Step(-1); err != nil {
+                b.Finish()
+                return err
+            }
+            return b.Finish()
+        })
+    })
+}
+
+// integrityCheck runs PRAGMA integrity_check and reports its findings.
+func integrityCheck(ctx context.Context, db *sql.DB) error {
+    rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+
+    var problems []string
+    for rows.Next() {
+        var line string
+        if err := rows.Scan(&line); err != nil {
+            return err
+        }
+        if line != "ok" {
+            problems = append(problems, line)
+        }
+    }
+    if err := rows.Err(); err != nil {
+        return err
+    }
+    if len(problems) > 0 {
+        return fmt.Errorf("%s", strings.Join(problems, "; "))
+    }
+    return nil
+}
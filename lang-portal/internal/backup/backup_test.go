@@ -0,0 +1,182 @@
+package backup
+
+import (
+    "context"
+    "database/sql"
+    "os"
+    "path/filepath"
+    "sync"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    _ "github.com/mattn/go-sqlite3"
+)
+
+func setupManager(t *testing.T, keep int) (*Manager, *sql.DB) {
+    db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "live.db"))
+    require.NoError(t, err)
+    t.Cleanup(func() { db.Close() })
+
+    _, err = db.Exec(`
+        CREATE TABLE words (id INTEGER PRIMARY KEY, japanese TEXT NOT NULL);
+        INSERT INTO words (japanese) VALUES ('猫'), ('犬');
+    `)
+    require.NoError(t, err)
+
+    m := NewManager(db, filepath.Join(t.TempDir(), "backups"), keep)
+    now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+    m.now = func() time.Time {
+        now = now.Add(time.Hour)
+        return now
+    }
+    return m, db
+}
+
+func count(t *testing.T, db *sql.DB) int {
+    var n int
+    require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM words").Scan(&n))
+    return n
+}
+
+func TestManager_SnapshotAndList(t *testing.T) {
+    m, _ := setupManager(t, 2)
+    ctx := context.Background()
+
+    first, err := m.Snapshot(ctx, ScheduledLabel)
+    require.NoError(t, err)
+    assert.Equal(t, "langportal-scheduled-20240301-130000.db", first.Name)
+    assert.Equal(t, "2024-03-01T13:00:00Z", first.CreatedAt)
+    assert.NotZero(t, first.SizeBytes)
+
+    snapshot, err := sql.Open("sqlite3", filepath.Join(m.dir, first.Name))
+    require.NoError(t, err)
+    defer snapshot.Close()
+    assert.Equal(t, 2, count(t, snapshot))
+
+    _, err = m.Snapshot(ctx, "manual")
+    require.NoError(t, err)
+    _, err = m.Snapshot(ctx, ScheduledLabel)
+    require.NoError(t, err)
+    _, err = m.Snapshot(ctx, ScheduledLabel)
+    require.NoError(t, err)
+
+    // Retention counts each label separately
+    list, err := m.List()
+    require.NoError(t, err)
+    var names []string
+    for _, b := range list {
+        names = append(names, b.Name)
+    }
+    assert.Equal(t, []string{
+        "langportal-scheduled-20240301-160000.db",
+        "langportal-scheduled-20240301-150000.db",
+        "langportal-manual-20240301-140000.db",
+    }, names)
+
+    _, err = m.Snapshot(ctx, "../escape")
+    assert.Error(t, err)
+}
+
+func TestManager_SnapshotsInSameSecond(t *testing.T) {
+    m, _ := setupManager(t, 1)
+    ctx := context.Background()
+    now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+    m.now = func() time.Time {
+        now = now.Add(250 * time.Millisecond)
+        return now
+    }
+
+    _, err := m.Snapshot(ctx, "manual")
+    require.NoError(t, err)
+    second, err := m.Snapshot(ctx, "manual")
+    require.NoError(t, err)
+    assert.Equal(t, "langportal-manual-20240301-120000.500.db", second.Name)
+
+    // The later snapshot is the one kept
+    _, err = os.Stat(filepath.Join(m.dir, second.Name))
+    assert.NoError(t, err)
+    list, err := m.List()
+    require.NoError(t, err)
+    require.Len(t, list, 1)
+    assert.Equal(t, second.Name, list[0].Name)
+}
+
+func TestManager_ConcurrentSnapshots(t *testing.T) {
+    m, _ := setupManager(t, 0)
+    ctx := context.Background()
+    // Every snapshot is taken in the same millisecond
+    m.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+    var wg sync.WaitGroup
+    errs := make([]error, 4)
+    for i := range errs {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            _, errs[i] = m.Snapshot(ctx, "manual")
+        }(i)
+    }
+    wg.Wait()
+    for _, err := range errs {
+        require.NoError(t, err)
+    }
+
+    list, err := m.List()
+    require.NoError(t, err)
+    var names []string
+    for _, b := range list {
+        names = append(names, b.Name)
+    }
+    // No snapshot replaced another
+    assert.ElementsMatch(t, []string{
+        "langportal-manual-20240301-120000.db",
+        "langportal-manual-20240301-120000.000.db",
+        "langportal-manual-20240301-120000.001.db",
+        "langportal-manual-20240301-120000.002.db",
+    }, names)
+}
+
+func TestManager_Restore(t *testing.T) {
+    m, db := setupManager(t, 0)
+    ctx := context.Background()
+
+    saved, err := m.Snapshot(ctx, "manual")
+    require.NoError(t, err)
+    require.NoError(t, m.Check(ctx, saved.Name))
+
+    _, err = db.Exec("DELETE FROM words")
+    require.NoError(t, err)
+
+    before, err := m.Restore(ctx, saved.Name)
+    require.NoError(t, err)
+    assert.Equal(t, "pre-restore", before.Label)
+    assert.Equal(t, 2, count(t, db))
+
+    _, err = m.Restore(ctx, "../live.db")
+    assert.ErrorContains(t, err, "invalid backup name")
+    _, err = m.Restore(ctx, "langportal-manual-20200101-000000.db")
+    assert.Error(t, err)
+}
+
+func TestManager_RestoreRejectsDamagedBackup(t *testing.T) {
+    m, db := setupManager(t, 0)
+    ctx := context.Background()
+
+    saved, err := m.Snapshot(ctx, "manual")
+    require.NoError(t, err)
+
+    // Overwrite a page in the middle of the file
+    path := filepath.Join(m.dir, saved.Name)
+    data, err := os.ReadFile(path)
+    require.NoError(t, err)
+    for i := len(data) / 2; i < len(data); i++ {
+        data[i] = 0xff
+    }
+    require.NoError(t, os.WriteFile(path, data, 0o644))
+
+    assert.Error(t, m.Check(ctx, saved.Name))
+    _, err = m.Restore(ctx, saved.Name)
+    assert.Error(t, err)
+    assert.Equal(t, 2, count(t, db))
+}
@@ -0,0 +1,518 @@
+// Package config loads the server settings. Values are layered with the
+// following precedence, highest first: command line flags, environment
+// variables, the YAML config file, built-in defaults.
+package config
+
+import (
+    "bytes"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "net"
+    "net/url"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+    "gopkg.in/yaml.v3"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/srs"
+)
+
+// DefaultFile is read when it exists and no other config file was given.
+const DefaultFile = "config/config.yaml"
+
+// Config holds every setting the server needs at startup.
+type Config struct {
+    DBPath       string           `yaml:"db_path"`
+    ListenAddr   string           `yaml:"listen_addr"`
+    LogLevel     string           `yaml:"log_level"`
+    // LogFormat is json for log collectors or text for reading in a
+    // terminal.
+    LogFormat string `yaml:"log_format"`
+    // SlowQueryThreshold is how long a repository call may take before it
+    // is logged as a slow query. Zero turns slow query logging off.
+    SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+    Server       ServerConfig     `yaml:"server"`
+    CORSOrigins  []string         `yaml:"cors_origins"`
+    RateLimit    RateLimitConfig  `yaml:"rate_limit"`
+    Pagination   PaginationConfig `yaml:"pagination"`
+    SRSAlgorithm string           `yaml:"srs_algorithm"`
+    // TimeZone is the IANA zone used for calendar-day stats. Empty means
+    // the server's local zone.
+    TimeZone string `yaml:"time_zone"`
+    // DuplicatePolicy is what creating a word that duplicates an existing
+    // one does: reject, upsert or allow.
+    DuplicatePolicy string `yaml:"duplicate_policy"`
+    // SeedDir holds the seed manifest and word lists a full reset imports.
+    SeedDir string        `yaml:"seed_dir"`
+    Backup  BackupConfig  `yaml:"backup"`
+    Health  HealthConfig  `yaml:"health"`
+    Tracing TracingConfig `yaml:"tracing"`
+}
+
+// ServerConfig holds the HTTP server timeouts.
+type ServerConfig struct {
+    // ReadTimeout bounds reading a request, body included.
+    ReadTimeout time.Duration `yaml:"read_timeout"`
+    // WriteTimeout bounds handling a request and writing its response.
+    WriteTimeout time.Duration `yaml:"write_timeout"`
+    // IdleTimeout is how long a keep-alive connection waits for the next
+    // request.
+    IdleTimeout time.Duration `yaml:"idle_timeout"`
+    // ShutdownTimeout is how long in-flight requests may take to finish
+    // once the server is asked to stop.
+    ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// BackupConfig controls database backups.
+type BackupConfig struct {
+    // Dir is the folder backups are written to.
+    Dir string `yaml:"dir"`
+    // Interval between scheduled backups, such as "24h". Zero disables
+    // scheduled backups.
+    Interval time.Duration `yaml:"interval"`
+    // Keep is the number of backups of each kind (scheduled, manual,
+    // pre-reset, ...) kept, the newest first. Zero keeps them all.
+    Keep int `yaml:"keep"`
+}
+
+// HealthConfig controls the readiness probe.
+type HealthConfig struct {
+    // Timeout bounds the checks of one probe together.
+    Timeout time.Duration `yaml:"timeout"`
+    // MinFreeMB is the disk space, in megabytes, the database folder needs
+    // available for the server to be ready.
+    MinFreeMB int `yaml:"min_free_mb"`
+}
+
+// TracingConfig controls OpenTelemetry tracing.
+type TracingConfig struct {
+    // Exporter is where spans go: none disables tracing, stdout prints
+    // them and otlp sends them to Endpoint.
+    Exporter string `yaml:"exporter"`
+    // Endpoint is the URL of the OTLP/HTTP collector, such as
+    // http://localhost:4318.
+    Endpoint string `yaml:"endpoint"`
+    // ServiceName identifies the server in the traces.
+    ServiceName string `yaml:"service_name"`
+}
+
+// RateLimitConfig limits requests per client IP. A zero RequestsPerMinute
+// disables rate limiting.
+type RateLimitConfig struct {
+    RequestsPerMinute int `yaml:"requests_per_minute"`
+    Burst             int `yaml:"burst"`
+}
+
+// PaginationConfig controls the page size of list endpoints.
+type PaginationConfig struct {
+    DefaultLimit int `yaml:"default_limit"`
+    MaxLimit     int `yaml:"max_limit"`
+}
+
+// Default returns the settings used when nothing overrides them.
+func Default() *Config {
+    return &Config{
+        DBPath:      "./langportal.db",
+        ListenAddr:  ":8080",
+        Server: ServerConfig{
+            ReadTimeout:     30 * time.Second,
+            WriteTimeout:    60 * time.Second,
+            IdleTimeout:     120 * time.Second,
+            ShutdownTimeout: 15 * time.Second,
+        },
+        LogLevel:    "info",
+        LogFormat:   "json",
+        SlowQueryThreshold: 200 * time.Millisecond,
+        CORSOrigins: []string{"http://localhost:3000"},
+        RateLimit: RateLimitConfig{
+            RequestsPerMinute: 100,
+            Burst:             20,
+        },
+        Pagination: PaginationConfig{
+            DefaultLimit: 100,
+            MaxLimit:     500,
+        },
+        SRSAlgorithm:    srs.DefaultAlgorithm,
+        DuplicatePolicy: string(models.DuplicateReject),
+        SeedDir:         "./seeds",
+        Backup: BackupConfig{
+            Dir:      "./backups",
+            Interval: 24 * time.Hour,
+            Keep:     7,
+        },
+        Health: HealthConfig{
+            Timeout:   2 * time.Second,
+            MinFreeMB: 100,
+        },
+        Tracing: TracingConfig{
+            Exporter:    "none",
+            Endpoint:    "http://localhost:4318",
+            ServiceName: "lang-portal",
+        },
+    }
+}
+
+// Location resolves TimeZone, falling back to the local zone.
+func (c *Config) Location() (*time.Location, error) {
+    if c.TimeZone == "" {
+        return time.Local, nil
+    }
+    return time.LoadLocation(c.TimeZone)
+}
+
+// ValidationError lists every invalid setting so they can all be fixed at
+// once instead of one restart at a time.
+type ValidationError struct {
+    Problems []string
+}
+
+func (e *ValidationError) Error() string {
+    return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the settings for values the server cannot start with.
+func (c *Config) Validate() error {
+    var problems []string
+    add := func(format string, args ...interface{}) {
+        problems = append(problems, fmt.Sprintf(format, args...))
+    }
+
+    if strings.TrimSpace(c.DBPath) == "" {
+        add("db_path must not be empty")
+    }
+    if _, port, err := net.SplitHostPort(c.ListenAddr); err != nil {
+        add("listen_addr %q is not a host:port address", c.ListenAddr)
+    } else if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+        add("listen_addr %q has an invalid port", c.ListenAddr)
+    }
+    if c.Server.ReadTimeout <= 0 {
+        add("server.read_timeout must be positive")
+    }
+    if c.Server.WriteTimeout <= 0 {
+        add("server.write_timeout must be positive")
+    }
+    if c.Server.IdleTimeout <= 0 {
+        add("server.idle_timeout must be positive")
+    }
+    if c.Server.ShutdownTimeout <= 0 {
+        add("server.shutdown_timeout must be positive")
+    }
+    switch c.LogLevel {
+    case "debug", "info", "warn", "error":
+    default:
+        add("log_level %q must be one of debug, info, warn, error", c.LogLevel)
+    }
+    switch c.LogFormat {
+    case "json", "text":
+    default:
+        add("log_format %q must be json or text", c.LogFormat)
+    }
+    if c.SlowQueryThreshold < 0 {
+        add("slow_query_threshold must not be negative")
+    }
+    for _, origin := range c.CORSOrigins {
+        if origin == "*" {
+            continue
+        }
+        u, err := url.Parse(origin)
+        if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+            add("cors_origins entry %q must be \"*\" or a scheme://host[:port] origin", origin)
+        }
+    }
+    if c.RateLimit.RequestsPerMinute < 0 {
+        add("rate_limit.requests_per_minute must not be negative")
+    }
+    if c.RateLimit.RequestsPerMinute > 0 && c.RateLimit.Burst < 1 {
+        add("rate_limit.burst must be at least 1 when rate limiting is enabled")
+    }
+    if c.Pagination.DefaultLimit < 1 {
+        add("pagination.default_limit must be at least 1")
+    }
+    if c.Pagination.MaxLimit < c.Pagination.DefaultLimit {
+        add("pagination.max_limit must not be lower than pagination.default_limit")
+    }
+    if _, err := srs.New(c.SRSAlgorithm); err != nil {
+        add("srs_algorithm: %v", err)
+    }
+    if strings.TrimSpace(c.SeedDir) == "" {
+        add("seed_dir must not be empty")
+    }
+    if strings.TrimSpace(c.Backup.Dir) == "" {
+        add("backup.dir must not be empty")
+    }
+    if c.Backup.Interval < 0 || (c.Backup.Interval > 0 && c.Backup.Interval < time.Minute) {
+        add("backup.interval must be 0 (disabled) or at least 1m")
+    }
+    if c.Backup.Keep < 0 {
+        add("backup.keep must not be negative")
+    }
+    if c.Health.Timeout <= 0 {
+        add("health.timeout must be positive")
+    }
+    if c.Health.MinFreeMB < 0 {
+        add("health.min_free_mb must not be negative")
+    }
+    switch c.Tracing.Exporter {
+    case "none", "stdout":
+    case "otlp":
+        if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+            add("tracing.endpoint %q must be an http:// or https:// URL", c.Tracing.Endpoint)
+        }
+    default:
+        add("tracing.exporter %q must be one of none, stdout, otlp", c.Tracing.Exporter)
+    }
+    if strings.TrimSpace(c.Tracing.ServiceName) == "" {
+        add("tracing.service_name must not be empty")
+    }
+    if _, err := models.ParseDuplicatePolicy(c.DuplicatePolicy); err != nil {
+        add("duplicate_policy: %v", err)
+    }
+    if _, err := c.Location(); err != nil {
+        add("time_zone %q is not a known IANA time zone", c.TimeZone)
+    }
+
+    if len(problems) > 0 {
+        return &ValidationError{Problems: problems}
+    }
+    return nil
+}
+
+// Load builds the configuration from args (without the program name) and
+// the environment, then validates it. The config file is chosen by the
+// -config flag, then the CONFIG_FILE variable, then DefaultFile if present.
+func Load(args []string, getenv func(string) string) (*Config, error) {
+    fs := flag.NewFlagSet("server", flag.ContinueOnError)
+    configFile := fs.String("config", "", "path to a YAML config file")
+    dbPath := fs.String("db", "", "path to the SQLite database")
+    listenAddr := fs.String("addr", "", "address to listen on, e.g. :8080")
+    readTimeout := fs.Duration("read-timeout", 0, "time allowed to read a request")
+    writeTimeout := fs.Duration("write-timeout", 0, "time allowed to handle a request and write the response")
+    idleTimeout := fs.Duration("idle-timeout", 0, "time a keep-alive connection waits for the next request")
+    shutdownTimeout := fs.Duration("shutdown-timeout", 0, "time in-flight requests get to finish on shutdown")
+    logLevel := fs.String("log-level", "", "log level: debug, info, warn or error")
+    logFormat := fs.String("log-format", "", "log format: json or text")
+    slowQuery := fs.Duration("slow-query-threshold", 0, "repository calls taking this long are logged as slow, 0 disables")
+    corsOrigins := fs.String("cors-origins", "", "comma separated list of allowed CORS origins")
+    rateLimit := fs.Int("rate-limit", 0, "requests per minute allowed per client IP, 0 disables")
+    rateBurst := fs.Int("rate-burst", 0, "number of requests a client may burst above the rate")
+    pageSize := fs.Int("page-size", 0, "default number of items per page")
+    maxPageSize := fs.Int("max-page-size", 0, "maximum number of items per page")
+    srsAlgorithm := fs.String("srs-algorithm", "", "spaced repetition algorithm: "+strings.Join(srs.Names(), ", "))
+    timeZone := fs.String("time-zone", "", "IANA time zone for calendar-day stats")
+    seedDir := fs.String("seed-dir", "", "folder with the seed files a full reset imports")
+    backupDir := fs.String("backup-dir", "", "folder database backups are written to")
+    backupInterval := fs.Duration("backup-interval", 0, "time between scheduled backups, 0 disables")
+    backupKeep := fs.Int("backup-keep", 0, "number of backups of each kind to keep, 0 keeps all")
+    healthTimeout := fs.Duration("health-timeout", 0, "time the readiness checks may take")
+    healthMinFree := fs.Int("health-min-free-mb", 0, "disk space in MB the database folder needs to be ready")
+    tracingExporter := fs.String("tracing-exporter", "", "where traces go: none, stdout or otlp")
+    tracingEndpoint := fs.String("tracing-endpoint", "", "URL of the OTLP/HTTP collector")
+    tracingService := fs.String("tracing-service-name", "", "service name reported in traces")
+    duplicatePolicy := fs.String("duplicate-policy", "", "what creating a duplicate word does: reject, upsert or allow")
+    if err := fs.Parse(args); err != nil {
+        return nil, err
+    }
+
+    cfg, err := load(*configFile, getenv)
+    if err != nil {
+        return nil, err
+    }
+
+    // Only flags given on the command line override lower layers
+    fs.Visit(func(f *flag.Flag) {
+        switch f.Name {
+        case "db":
+            cfg.DBPath = *dbPath
+        case "addr":
+            cfg.ListenAddr = *listenAddr
+        case "read-timeout":
+            cfg.Server.ReadTimeout = *readTimeout
+        case "write-timeout":
+            cfg.Server.WriteTimeout = *writeTimeout
+        case "idle-timeout":
+            cfg.Server.IdleTimeout = *idleTimeout
+        case "shutdown-timeout":
+            cfg.Server.ShutdownTimeout = *shutdownTimeout
+        case "log-level":
+            cfg.LogLevel = *logLevel
+        case "log-format":
+            cfg.LogFormat = *logFormat
+        case "slow-query-threshold":
+            cfg.SlowQueryThreshold = *slowQuery
+        case "cors-origins":
+            cfg.CORSOrigins = splitList(*corsOrigins)
+        case "rate-limit":
+            cfg.RateLimit.RequestsPerMinute = *rateLimit
+        case "rate-burst":
+            cfg.RateLimit.Burst = *rateBurst
+        case "page-size":
+            cfg.Pagination.DefaultLimit = *pageSize
+        case "max-page-size":
+            cfg.Pagination.MaxLimit = *maxPageSize
+        case "srs-algorithm":
+            cfg.SRSAlgorithm = *srsAlgorithm
+        case "time-zone":
+            cfg.TimeZone = *timeZone
+        case "duplicate-policy":
+            cfg.DuplicatePolicy = *duplicatePolicy
+        case "seed-dir":
+            cfg.SeedDir = *seedDir
+        case "backup-dir":
+            cfg.Backup.Dir = *backupDir
+        case "backup-interval":
+            cfg.Backup.Interval = *backupInterval
+        case "backup-keep":
+            cfg.Backup.Keep = *backupKeep
+        case "health-timeout":
+            cfg.Health.Timeout = *healthTimeout
+        case "health-min-free-mb":
+            cfg.Health.MinFreeMB = *healthMinFree
+        case "tracing-exporter":
+            cfg.Tracing.Exporter = *tracingExporter
+        case "tracing-endpoint":
+            cfg.Tracing.Endpoint = *tracingEndpoint
+        case "tracing-service-name":
+            cfg.Tracing.ServiceName = *tracingService
+        }
+    })
+
+    if err := cfg.Validate(); err != nil {
+        return nil, err
+    }
+    return cfg, nil
+}
+
+// LoadFile builds the configuration like Load but without the server's
+// command line, for tools that have flags of their own and then override
+// the few settings they take, such as the database path. The config file is
+// path when given, then the CONFIG_FILE variable, then DefaultFile if
+// present.
+func LoadFile(path string, getenv func(string) string) (*Config, error) {
+    cfg, err := load(path, getenv)
+    if err != nil {
+        return nil, err
+    }
+    if err := cfg.Validate(); err != nil {
+        return nil, err
+    }
+    return cfg, nil
+}
+
+// load layers the config file and the environment over the defaults.
+func load(path string, getenv func(string) string) (*Config, error) {
+    cfg := Default()
+
+    required := true
+    if path == "" {
+        path = getenv("CONFIG_FILE")
+    }
+    if path == "" {
+        path, required = DefaultFile, false
+    }
+    if err := cfg.loadFile(path, required); err != nil {
+        return nil, err
+    }
+
+    if err := cfg.loadEnv(getenv); err != nil {
+        return nil, err
+    }
+    return cfg, nil
+}
+
+func (c *Config) loadFile(path string, required bool) error {
+    body, err := os.ReadFile(path)
+    if errors.Is(err, os.ErrNotExist) && !required {
+        return nil
+    }
+    if err != nil {
+        return fmt.Errorf("read config file: %w", err)
+    }
+
+    dec := yaml.NewDecoder(bytes.NewReader(body))
+    dec.KnownFields(true)
+    if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+        return fmt.Errorf("parse config file %s: %w", path, err)
+    }
+    return nil
+}
+
+func (c *Config) loadEnv(getenv func(string) string) error {
+    strs := map[string]*string{
+        "DATABASE_PATH":        &c.DBPath,
+        "LISTEN_ADDR":          &c.ListenAddr,
+        "LOG_LEVEL":            &c.LogLevel,
+        "LOG_FORMAT":           &c.LogFormat,
+        "SRS_ALGORITHM":        &c.SRSAlgorithm,
+        "TIME_ZONE":            &c.TimeZone,
+        "DUPLICATE_POLICY":     &c.DuplicatePolicy,
+        "SEED_DIR":             &c.SeedDir,
+        "BACKUP_DIR":           &c.Backup.Dir,
+        "TRACING_EXPORTER":     &c.Tracing.Exporter,
+        "TRACING_ENDPOINT":     &c.Tracing.Endpoint,
+        "TRACING_SERVICE_NAME": &c.Tracing.ServiceName,
+    }
+    for name, dst := range strs {
+        if v := getenv(name); v != "" {
+            *dst = v
+        }
+    }
+
+    ints := map[string]*int{
+        "RATE_LIMIT_REQUESTS_PER_MINUTE": &c.RateLimit.RequestsPerMinute,
+        "RATE_LIMIT_BURST":               &c.RateLimit.Burst,
+        "PAGINATION_DEFAULT_LIMIT":       &c.Pagination.DefaultLimit,
+        "PAGINATION_MAX_LIMIT":           &c.Pagination.MaxLimit,
+        "BACKUP_KEEP":                    &c.Backup.Keep,
+        "HEALTH_MIN_FREE_MB":             &c.Health.MinFreeMB,
+    }
+    for name, dst := range ints {
+        v := getenv(name)
+        if v == "" {
+            continue
+        }
+        n, err := strconv.Atoi(v)
+        if err != nil {
+            return fmt.Errorf("environment variable %s: %q is not a number", name, v)
+        }
+        *dst = n
+    }
+
+    durations := map[string]*time.Duration{
+        "SERVER_READ_TIMEOUT":     &c.Server.ReadTimeout,
+        "SERVER_WRITE_TIMEOUT":    &c.Server.WriteTimeout,
+        "SERVER_IDLE_TIMEOUT":     &c.Server.IdleTimeout,
+        "SERVER_SHUTDOWN_TIMEOUT": &c.Server.ShutdownTimeout,
+        "BACKUP_INTERVAL":         &c.Backup.Interval,
+        "HEALTH_TIMEOUT":          &c.Health.Timeout,
+        "SLOW_QUERY_THRESHOLD":    &c.SlowQueryThreshold,
+    }
+    for name, dst := range durations {
+        v := getenv(name)
+        if v == "" {
+            continue
+        }
+        d, err := time.ParseDuration(v)
+        if err != nil {
+            return fmt.Errorf("environment variable %s: %q is not a duration", name, v)
+        }
+        *dst = d
+    }
+
+    if v := getenv("CORS_ALLOWED_ORIGINS"); v != "" {
+        c.CORSOrigins = splitList(v)
+    }
+    return nil
+}
+
+func splitList(s string) []string {
+    var items []string
+    for _, item := range strings.Split(s, ",") {
+        if item = strings.TrimSpace(item); item != "" {
+            items = append(items, item)
+        }
+    }
+    return items
+}
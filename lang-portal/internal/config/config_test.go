@@ -0,0 +1,139 @@
+package config
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) func(string) string {
+    return func(name string) string { return vars[name] }
+}
+
+func writeFile(t *testing.T, body string) string {
+    path := filepath.Join(t.TempDir(), "config.yaml")
+    require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+    return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+    // Run from an empty directory so a developer's config/config.yaml is not picked up
+    wd, err := os.Getwd()
+    require.NoError(t, err)
+    require.NoError(t, os.Chdir(t.TempDir()))
+    t.Cleanup(func() { os.Chdir(wd) })
+
+    cfg, err := Load(nil, env(nil))
+    require.NoError(t, err)
+    assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+    path := writeFile(t, `
+db_path: /from/file.db
+listen_addr: ":9000"
+log_level: warn
+log_format: text
+cors_origins: [https://file.example.com]
+pagination:
+  default_limit: 25
+  max_limit: 50
+`)
+
+    cfg, err := Load(
+        []string{"-config", path, "-addr", "127.0.0.1:7000"},
+        env(map[string]string{"LISTEN_ADDR": ":9100", "LOG_LEVEL": "error", "PAGINATION_MAX_LIMIT": "75", "HEALTH_TIMEOUT": "500ms", "SLOW_QUERY_THRESHOLD": "1s"}),
+    )
+    require.NoError(t, err)
+
+    assert.Equal(t, "/from/file.db", cfg.DBPath)
+    assert.Equal(t, "127.0.0.1:7000", cfg.ListenAddr)
+    assert.Equal(t, "error", cfg.LogLevel)
+    assert.Equal(t, "text", cfg.LogFormat)
+    assert.Equal(t, time.Second, cfg.SlowQueryThreshold)
+    assert.Equal(t, []string{"https://file.example.com"}, cfg.CORSOrigins)
+    assert.Equal(t, 25, cfg.Pagination.DefaultLimit)
+    assert.Equal(t, 75, cfg.Pagination.MaxLimit)
+    assert.Equal(t, HealthConfig{Timeout: 500 * time.Millisecond, MinFreeMB: 100}, cfg.Health)
+    // Untouched settings keep their defaults
+    assert.Equal(t, 100, cfg.RateLimit.RequestsPerMinute)
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+    path := writeFile(t, "srs_algorithm: fsrs\nbackup:\n  interval: 6h\n")
+
+    cfg, err := Load(
+        []string{"-shutdown-timeout", "5s"},
+        env(map[string]string{"CONFIG_FILE": path, "CORS_ALLOWED_ORIGINS": "*, http://a.test", "BACKUP_KEEP": "3", "SERVER_WRITE_TIMEOUT": "2m", "TRACING_EXPORTER": "otlp"}),
+    )
+    require.NoError(t, err)
+    assert.Equal(t, "fsrs", cfg.SRSAlgorithm)
+    assert.Equal(t, "reject", cfg.DuplicatePolicy)
+    assert.Equal(t, BackupConfig{Dir: "./backups", Interval: 6 * time.Hour, Keep: 3}, cfg.Backup)
+    assert.Equal(t, []string{"*", "http://a.test"}, cfg.CORSOrigins)
+    assert.Equal(t, TracingConfig{Exporter: "otlp", Endpoint: "http://localhost:4318", ServiceName: "lang-portal"}, cfg.Tracing)
+    assert.Equal(t, ServerConfig{ReadTimeout: 30 * time.Second, WriteTimeout: 2 * time.Minute, IdleTimeout: 2 * time.Minute, ShutdownTimeout: 5 * time.Second}, cfg.Server)
+}
+
+func TestLoadFile(t *testing.T) {
+    path := writeFile(t, "db_path: /from/file.db\nseed_dir: /from/file/seeds\n")
+
+    cfg, err := LoadFile("", env(map[string]string{"CONFIG_FILE": path, "SEED_DIR": "/from/env/seeds"}))
+    require.NoError(t, err)
+    assert.Equal(t, "/from/file.db", cfg.DBPath)
+    assert.Equal(t, "/from/env/seeds", cfg.SeedDir)
+
+    // An explicit path wins over CONFIG_FILE, and the result is validated
+    _, err = LoadFile(writeFile(t, "log_level: loud\n"), env(map[string]string{"CONFIG_FILE": path}))
+    var verr *ValidationError
+    assert.ErrorAs(t, err, &verr)
+}
+
+func TestLoad_Errors(t *testing.T) {
+    _, err := Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}, env(nil))
+    assert.ErrorContains(t, err, "read config file")
+
+    _, err = Load([]string{"-config", writeFile(t, "db_pth: typo.db\n")}, env(nil))
+    assert.ErrorContains(t, err, "field db_pth not found")
+
+    _, err = Load([]string{"-config", writeFile(t, "")}, env(map[string]string{"RATE_LIMIT_BURST": "lots"}))
+    assert.EqualError(t, err, `environment variable RATE_LIMIT_BURST: "lots" is not a number`)
+
+    _, err = Load([]string{"-config", writeFile(t, "")}, env(map[string]string{"SERVER_IDLE_TIMEOUT": "forever"}))
+    assert.EqualError(t, err, `environment variable SERVER_IDLE_TIMEOUT: "forever" is not a duration`)
+}
+
+func TestValidate(t *testing.T) {
+    cfg := Default()
+    cfg.DBPath = " "
+    cfg.ListenAddr = "8080"
+    cfg.LogLevel = "verbose"
+    cfg.LogFormat = "xml"
+    cfg.SlowQueryThreshold = -time.Second
+    cfg.CORSOrigins = []string{"*", "localhost:3000", "http://ok.test"}
+    cfg.RateLimit.Burst = 0
+    cfg.Pagination = PaginationConfig{DefaultLimit: 50, MaxLimit: 10}
+    cfg.SRSAlgorithm = "leitner"
+    cfg.TimeZone = "Mars/Olympus"
+    cfg.DuplicatePolicy = "merge"
+    cfg.Backup.Dir = ""
+    cfg.Backup.Interval = time.Second
+    cfg.Server.ShutdownTimeout = 0
+    cfg.Health.MinFreeMB = -1
+    cfg.Tracing = TracingConfig{Exporter: "otlp", Endpoint: "localhost:4318", ServiceName: "lang-portal"}
+
+    err := cfg.Validate()
+    var verr *ValidationError
+    require.ErrorAs(t, err, &verr)
+    assert.Len(t, verr.Problems, 16)
+    assert.Contains(t, verr.Problems, `listen_addr "8080" is not a host:port address`)
+    assert.Contains(t, verr.Problems, `cors_origins entry "localhost:3000" must be "*" or a scheme://host[:port] origin`)
+    assert.Contains(t, verr.Problems, `tracing.endpoint "localhost:4318" must be an http:// or https:// URL`)
+    assert.Contains(t, verr.Problems, `log_format "xml" must be json or text`)
+    assert.Contains(t, verr.Problems, `duplicate_policy: unknown duplicate policy "merge", must be one of reject, upsert, allow`)
+
+    assert.NoError(t, Default().Validate())
+}
@@ -0,0 +1,186 @@
+// Package health answers the liveness and readiness probes: the process is
+// up, and the database answers, has a current schema and has disk space
+// left to grow.
+package health
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "path/filepath"
+    "runtime"
+    "runtime/debug"
+    "strings"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/migrate"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+// Version is the release of the binary, set at build time with
+// -ldflags "-X github.com/karl247ai/lang-portal/internal/health.Version=1.4.0".
+var Version = "dev"
+
+// Check statuses.
+const (
+    StatusOK   = "ok"
+    StatusFail = "fail"
+)
+
+// errDiskUnsupported is returned by diskSpace where it cannot be measured,
+// which skips the disk check rather than failing it.
+var errDiskUnsupported = errors.New("disk space is not reported on this platform")
+
+// Checker runs the readiness checks against the database at a path.
+type Checker struct {
+    db       *sql.DB
+    migrator *migrate.Migrator
+    dir      string
+    timeout  time.Duration
+    minFree  uint64
+    started  time.Time
+    build    models.BuildInfo
+}
+
+// NewChecker checks db, whose file lives at dbPath, against the migrations
+// of migrator. The checks of one probe share timeout, and the disk check
+// fails below minFree bytes available.
+func NewChecker(db *sql.DB, migrator *migrate.Migrator, dbPath string, timeout time.Duration, minFree uint64) *Checker {
+    return &Checker{
+        db:       db,
+        migrator: migrator,
+        dir:      filepath.Dir(dbPath),
+        timeout:  timeout,
+        minFree:  minFree,
+        started:  time.Now(),
+        build:    buildInfo(),
+    }
+}
+
+// Live reports that the process is running. It touches nothing else, so a
+// slow database never gets the server restarted.
+func (c *Checker) Live() *models.HealthReport {
+    return &models.HealthReport{
+        Status: StatusOK,
+        Build:  c.build,
+        Uptime: time.Since(c.started).Round(time.Second).String(),
+    }
+}
+
+// Ready runs every check and reports ok only when all of them pass.
+func (c *Checker) Ready(ctx context.Context) *models.HealthReport {
+    ctx, cancel := context.WithTimeout(ctx, c.timeout)
+    defer cancel()
+
+    report := c.Live()
+    report.Checks = map[string]models.HealthCheck{
+        "database":   c.checkDatabase(ctx),
+        "migrations": c.checkMigrations(ctx),
+        "disk":       c.checkDisk(),
+    }
+    for _, check := range report.Checks {
+        if check.Status != StatusOK {
+            report.Status = StatusFail
+        }
+    }
+    return report
+}
+
+func (c *Checker) checkDatabase(ctx context.Context) models.HealthCheck {
+    start := time.Now()
+    // Ping alone does no I/O with SQLite; reading the schema does
+    var tables int
+    err := c.db.PingContext(ctx)
+    if err == nil {
+        err = c.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master").Scan(&tables)
+    }
+    if err != nil {
+        return failed(err)
+    }
+    return models.HealthCheck{
+        Status:  StatusOK,
+        Details: map[string]interface{}{"latency_ms": float64(time.Since(start).Microseconds()) / 1000},
+    }
+}
+
+func (c *Checker) checkMigrations(ctx context.Context) models.HealthCheck {
+    if err := c.migrator.Verify(ctx); err != nil {
+        return failed(err)
+    }
+    statuses, err := c.migrator.Status(ctx)
+    if err != nil {
+        return failed(err)
+    }
+
+    var pending []string
+    latest := ""
+    for _, s := range statuses {
+        if s.Applied {
+            latest = s.Name
+        } else {
+            pending = append(pending, s.Name)
+        }
+    }
+    details := map[string]interface{}{"applied": len(statuses) - len(pending), "latest": latest}
+    if len(pending) > 0 {
+        details["pending"] = pending
+        return models.HealthCheck{
+            Status:  StatusFail,
+            Message: fmt.Sprintf("%d pending migrations: %s", len(pending), strings.Join(pending, ", ")),
+            Details: details,
+        }
+    }
+    return models.HealthCheck{Status: StatusOK, Details: details}
+}
+
+func (c *Checker) checkDisk() models.HealthCheck {
+    free, total, err := diskSpace(c.dir)
+    if err == errDiskUnsupported {
+        return models.HealthCheck{Status: StatusOK, Message: err.Error()}
+    }
+    if err != nil {
+        return failed(err)
+    }
+
+    check := models.HealthCheck{
+        Status: StatusOK,
+        Details: map[string]interface{}{
+            "path":           c.dir,
+            "free_bytes":     free,
+            "total_bytes":    total,
+            "min_free_bytes": c.minFree,
+        },
+    }
+    if free < c.minFree {
+        check.Status = StatusFail
+        check.Message = fmt.Sprintf("%d MB available, at least %d MB required", free>>20, c.minFree>>20)
+    }
+    return check
+}
+
+func failed(err error) models.HealthCheck {
+    return models.HealthCheck{Status: StatusFail, Message: err.Error()}
+}
+
+// buildInfo combines Version with the VCS details the Go toolchain embeds.
+func buildInfo() models.BuildInfo {
+    b := models.BuildInfo{Version: Version, GoVersion: runtime.Version()}
+    info, ok := debug.ReadBuildInfo()
+    if !ok {
+        return b
+    }
+    if b.Version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+        b.Version = info.Main.Version
+    }
+    for _, s := range info.Settings {
+        switch s.Key {
+        case "vcs.revision":
+            b.Commit = s.Value
+        case "vcs.time":
+            b.CommitTime = s.Value
+        case "vcs.modified":
+            b.Modified = s.Value == "true"
+        }
+    }
+    return b
+}
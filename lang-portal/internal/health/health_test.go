@@ -0,0 +1,71 @@
+package health
+
+import (
+    "context"
+    "database/sql"
+    "path/filepath"
+    "testing"
+    "testing/fstest"
+    "time"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/karl247ai/lang-portal/internal/migrate"
+    _ "github.com/mattn/go-sqlite3"
+)
+
+func testFS() fstest.MapFS {
+    return fstest.MapFS{
+        "001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER);")},
+        "001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+    }
+}
+
+func setup(t *testing.T) (*sql.DB, string) {
+    path := filepath.Join(t.TempDir(), "test.db")
+    db, err := sql.Open("sqlite3", path)
+    require.NoError(t, err)
+    t.Cleanup(func() { db.Close() })
+
+    m, err := migrate.New(db, testFS())
+    require.NoError(t, err)
+    _, err = m.Up(context.Background())
+    require.NoError(t, err)
+    return db, path
+}
+
+func TestChecker_Ready(t *testing.T) {
+    db, path := setup(t)
+    ctx := context.Background()
+
+    m, err := migrate.New(db, testFS())
+    require.NoError(t, err)
+    checker := NewChecker(db, m, path, time.Second, 0)
+
+    report := checker.Ready(ctx)
+    assert.Equal(t, StatusOK, report.Status)
+    assert.Len(t, report.Checks, 3)
+    assert.Equal(t, 1, report.Checks["migrations"].Details["applied"])
+    assert.Equal(t, "001_create_a", report.Checks["migrations"].Details["latest"])
+    assert.NotEmpty(t, report.Build.GoVersion)
+
+    // A migration the database has not seen yet
+    pending := testFS()
+    pending["002_create_b.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE b (id INTEGER);")}
+    m, err = migrate.New(db, pending)
+    require.NoError(t, err)
+    report = NewChecker(db, m, path, time.Second, 0).Ready(ctx)
+    assert.Equal(t, StatusFail, report.Status)
+    assert.Equal(t, "1 pending migrations: 002_create_b", report.Checks["migrations"].Message)
+    assert.Equal(t, StatusOK, report.Checks["database"].Status)
+
+    // More free space required than any disk has
+    report = NewChecker(db, m, path, time.Second, 1<<62).Ready(ctx)
+    assert.Equal(t, StatusFail, report.Checks["disk"].Status)
+
+    // The database is gone while liveness still holds
+    db.Close()
+    report = checker.Ready(ctx)
+    assert.Equal(t, StatusFail, report.Status)
+    assert.Equal(t, StatusFail, report.Checks["database"].Status)
+    assert.Equal(t, StatusOK, checker.Live().Status)
+}
@@ -0,0 +1,73 @@
+// Package logging builds the server's structured logger and carries a
+// per-request logger in the context, so everything done for a request is
+// logged with its request ID.
+package logging
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "log/slog"
+    "time"
+)
+
+// New returns a logger writing to w at level (debug, info, warn or error)
+// in format (json or text).
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+    var lvl slog.Level
+    if err := lvl.UnmarshalText([]byte(level)); err != nil {
+        return nil, fmt.Errorf("unknown log level %q", level)
+    }
+
+    opts := &slog.HandlerOptions{Level: lvl}
+    switch format {
+    case "json":
+        return slog.New(slog.NewJSONHandler(w, opts)), nil
+    case "text":
+        return slog.New(slog.NewTextHandler(w, opts)), nil
+    default:
+        return nil, fmt.Errorf("unknown log format %q", format)
+    }
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+    return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+    if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+        return logger
+    }
+    return slog.Default()
+}
+
+// QueryLogger logs repository methods with the logger of their context:
+// at debug level every call, and as a warning the calls slower than its
+// threshold. It is a repository.QueryObserver.
+type QueryLogger struct {
+    threshold time.Duration
+}
+
+// NewQueryLogger warns about calls taking threshold or longer; zero turns
+// the warning off.
+func NewQueryLogger(threshold time.Duration) *QueryLogger {
+    return &QueryLogger{threshold: threshold}
+}
+
+func (q *QueryLogger) ObserveQuery(ctx context.Context, repository, method string) (context.Context, func()) {
+    start := time.Now()
+    return ctx, func() {
+        took := time.Since(start)
+        logger := FromContext(ctx)
+        attrs := []any{"repository", repository, "method", method, "duration_ms", float64(took.Microseconds()) / 1000}
+        if q.threshold > 0 && took >= q.threshold {
+            logger.WarnContext(ctx, "slow query", append(attrs, "threshold_ms", float64(q.threshold.Microseconds())/1000)...)
+            return
+        }
+        logger.DebugContext(ctx, "query", attrs...)
+    }
+}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "strings"
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+    var buf bytes.Buffer
+    logger, err := New(&buf, "warn", "json")
+    require.NoError(t, err)
+
+    logger.Info("dropped")
+    logger.Warn("kept", "n", 1)
+    var entry map[string]interface{}
+    require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+    assert.Equal(t, "kept", entry["msg"])
+    assert.Equal(t, "WARN", entry["level"])
+
+    buf.Reset()
+    logger, err = New(&buf, "debug", "text")
+    require.NoError(t, err)
+    logger.Debug("hello")
+    assert.Contains(t, buf.String(), "level=DEBUG msg=hello")
+
+    _, err = New(&buf, "verbose", "json")
+    assert.EqualError(t, err, `unknown log level "verbose"`)
+    _, err = New(&buf, "info", "xml")
+    assert.EqualError(t, err, `unknown log format "xml"`)
+}
+
+func TestQueryLogger(t *testing.T) {
+    var buf bytes.Buffer
+    logger, err := New(&buf, "info", "json")
+    require.NoError(t, err)
+    ctx := WithLogger(context.Background(), logger.With("request_id", "abc"))
+    assert.Same(t, logger, FromContext(WithLogger(context.Background(), logger)))
+
+    // Fast calls only show up at debug level
+    _, done := NewQueryLogger(time.Hour).ObserveQuery(ctx, "words", "GetWord")
+    done()
+    assert.Empty(t, buf.String())
+
+    _, done = NewQueryLogger(time.Millisecond).ObserveQuery(ctx, "words", "GetWords")
+    time.Sleep(2 * time.Millisecond)
+    done()
+    lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+    require.Len(t, lines, 1)
+    var entry map[string]interface{}
+    require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+    assert.Equal(t, "slow query", entry["msg"])
+    assert.Equal(t, "abc", entry["request_id"])
+    assert.Equal(t, "words", entry["repository"])
+    assert.Equal(t, "GetWords", entry["method"])
+    assert.Equal(t, float64(1), entry["threshold_ms"])
+}
@@ -0,0 +1,110 @@
+// Package metrics exposes Prometheus metrics: request counts and latency
+// per route, repository call durations, database pool stats and study
+// counters.
+package metrics
+
+import (
+    "context"
+    "database/sql"
+    "net/http"
+    "strconv"
+    "time"
+    "github.com/gin-gonic/gin"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/collectors"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+    "github.com/karl247ai/lang-portal/internal/repository"
+)
+
+const namespace = "langportal"
+
+// unmatchedRoute labels requests no route matched, so unknown paths do not
+// each get their own series.
+const unmatchedRoute = "unmatched"
+
+var (
+    httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "http_requests_total",
+        Help:      "HTTP requests by method, route template and status.",
+    }, []string{"method", "route", "status"})
+
+    // The 0.2 bucket makes the 200ms p95 target directly readable
+    httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Name:      "http_request_duration_seconds",
+        Help:      "HTTP request latency by method, route template and status.",
+        Buckets:   []float64{.005, .01, .025, .05, .1, .2, .3, .5, 1, 2.5, 5, 10},
+    }, []string{"method", "route", "status"})
+
+    queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Name:      "db_query_duration_seconds",
+        Help:      "Duration of repository methods, including their queries and transactions.",
+        Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+    }, []string{"repository", "method"})
+
+    reviewsRecorded = promauto.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "reviews_recorded_total",
+        Help:      "Word reviews recorded, by whether the answer was correct.",
+    }, []string{"correct"})
+
+    sessionsStarted = promauto.NewCounter(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "study_sessions_started_total",
+        Help:      "Study sessions started.",
+    })
+)
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+    return promhttp.Handler()
+}
+
+// Middleware counts and times every request by its route template, such as
+// /api/v1/words/:id, rather than by its path.
+func Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        start := time.Now()
+        c.Next()
+
+        route := c.FullPath()
+        if route == "" {
+            route = unmatchedRoute
+        }
+        status := strconv.Itoa(c.Writer.Status())
+        httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+        httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+    }
+}
+
+// RegisterDB exports the connection pool stats of db.
+func RegisterDB(db *sql.DB) error {
+    return prometheus.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// ObserveQueries times every repository method from now on.
+func ObserveQueries() {
+    repository.Observe(queryObserver{})
+}
+
+type queryObserver struct{}
+
+func (queryObserver) ObserveQuery(ctx context.Context, repo, method string) (context.Context, func()) {
+    start := time.Now()
+    return ctx, func() {
+        queryDuration.WithLabelValues(repo, method).Observe(time.Since(start).Seconds())
+    }
+}
+
+// ReviewRecorded counts a saved review.
+func ReviewRecorded(correct bool) {
+    reviewsRecorded.WithLabelValues(strconv.FormatBool(correct)).Inc()
+}
+
+// SessionStarted counts a saved study session.
+func SessionStarted() {
+    sessionsStarted.Inc()
+}
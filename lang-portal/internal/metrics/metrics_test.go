@@ -0,0 +1,43 @@
+package metrics
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "github.com/gin-gonic/gin"
+    "github.com/prometheus/client_golang/prometheus/testutil"
+    "github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.Use(Middleware())
+    r.GET("/words/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+    r.GET("/metrics", gin.WrapH(Handler()))
+
+    for _, path := range []string{"/words/1", "/words/2", "/nowhere"} {
+        r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+    }
+
+    // Both word requests share the route template
+    assert.Equal(t, 2.0, testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/words/:id", "204")))
+    assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("GET", unmatchedRoute, "404")))
+
+    ctx, done := queryObserver{}.ObserveQuery(context.Background(), "words", "GetWord")
+    assert.NotNil(t, ctx)
+    done()
+    ReviewRecorded(true)
+    SessionStarted()
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+    body := w.Body.String()
+    assert.Contains(t, body, `langportal_http_request_duration_seconds_bucket{method="GET",route="/words/:id",status="204",le="0.2"} 2`)
+    assert.Contains(t, body, `langportal_db_query_duration_seconds_count{method="GetWord",repository="words"} 1`)
+    assert.Contains(t, body, `langportal_reviews_recorded_total{correct="true"} 1`)
+    assert.Contains(t, body, "langportal_study_sessions_started_total 1")
+    assert.False(t, strings.Contains(body, "/nowhere"))
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+    "github.com/gin-gonic/gin"
+    "net/http"
+)
+
+// CORS allows browsers on the given origins to call the API. An origin of
+// "*" allows any origin.
+func CORS(origins []string) gin.HandlerFunc {
+    allowed := map[string]bool{}
+    for _, origin := range origins {
+        allowed[origin] = true
+    }
+
+    return func(c *gin.Context) {
+        origin := c.GetHeader("Origin")
+        if origin == "" {
+            c.Next()
+            return
+        }
+
+        c.Header("Vary", "Origin")
+        if !allowed["*"] && !allowed[origin] {
+            if c.Request.Method == http.MethodOptions {
+                c.AbortWithStatus(http.StatusForbidden)
+                return
+            }
+            c.Next()
+            return
+        }
+
+        c.Header("Access-Control-Allow-Origin", origin)
+        if c.Request.Method == http.MethodOptions {
+            c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+            c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+            c.Header("Access-Control-Max-Age", "600")
+            c.AbortWithStatus(http.StatusNoContent)
+            return
+        }
+        c.Next()
+    }
+}
@@ -1,26 +1,90 @@
 package middleware
 
 import (
+    "errors"
     "github.com/gin-gonic/gin"
     "net/http"
+    "github.com/karl247ai/lang-portal/internal/logging"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
 )
 
+// Error codes returned in the error envelope.
+const (
+    CodeNotFound             = "RESOURCE_NOT_FOUND"
+    CodeConflict             = "DUPLICATE_ENTRY"
+    CodeValidation           = "VALIDATION_ERROR"
+    CodeRateLimitExceeded    = "RATE_LIMIT_EXCEEDED"
+    CodeConfirmationRequired = "CONFIRMATION_REQUIRED"
+    CodeInternal             = "INTERNAL_SERVER_ERROR"
+)
+
+// AppError is an error raised outside the repository, e.g. by another
+// middleware, that already knows its HTTP status and code.
 type AppError struct {
-    Code    int    `json:"code"`
-    Message string `json:"message"`
+    Status  int                    `json:"-"`
+    Code    string                 `json:"code"`
+    Message string                 `json:"message"`
+    Details map[string]interface{} `json:"details,omitempty"`
 }
 
+func (e AppError) Error() string {
+    return e.Message
+}
+
+// ErrorHandler renders the last error attached with c.Error as
+// {"error": {"code", "message", "details"}}. Repository sentinel errors map
+// to their status; anything else is logged and reported as a 500 without
+// leaking internals.
 func ErrorHandler() gin.HandlerFunc {
     return func(c *gin.Context) {
         c.Next()
 
-        if len(c.Errors) > 0 {
-            err := c.Errors.Last()
-            if appErr, ok := err.Err.(AppError); ok {
-                c.JSON(appErr.Code, gin.H{"error": appErr.Message})
-                return
-            }
-            c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        if len(c.Errors) == 0 || c.Writer.Written() {
+            return
+        }
+
+        status, body := errorBody(c.Errors.Last().Err)
+        if status == http.StatusInternalServerError {
+            logging.FromContext(c.Request.Context()).Error("request failed",
+                "method", c.Request.Method, "path", c.Request.URL.Path, "error", c.Errors.Last().Err)
+        }
+        c.JSON(status, models.ErrorResponse{Error: body})
+    }
+}
+
+func errorBody(err error) (int, models.ErrorBody) {
+    body := models.ErrorBody{Message: err.Error(), Details: map[string]interface{}{}}
+
+    var appErr AppError
+    if errors.As(err, &appErr) {
+        body.Code = appErr.Code
+        for k, v := range appErr.Details {
+            body.Details[k] = v
+        }
+        return appErr.Status, body
+    }
+
+    var repoErr *repository.Error
+    if errors.As(err, &repoErr) {
+        for k, v := range repoErr.Details {
+            body.Details[k] = v
         }
     }
-}
\ No newline at end of file
+
+    switch {
+    case errors.Is(err, repository.ErrNotFound):
+        body.Code = CodeNotFound
+        return http.StatusNotFound, body
+    case errors.Is(err, repository.ErrConflict):
+        body.Code = CodeConflict
+        return http.StatusConflict, body
+    case errors.Is(err, repository.ErrValidation):
+        body.Code = CodeValidation
+        return http.StatusBadRequest, body
+    }
+
+    body.Code = CodeInternal
+    body.Message = "internal server error"
+    return http.StatusInternalServerError, body
+}
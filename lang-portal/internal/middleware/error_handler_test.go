@@ -0,0 +1,62 @@
+package middleware
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "github.com/gin-gonic/gin"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+)
+
+func serveError(t *testing.T, err error) (int, models.ErrorBody) {
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.Use(ErrorHandler())
+    r.GET("/", func(c *gin.Context) { c.Error(err) })
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+    var resp models.ErrorResponse
+    require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+    return w.Code, resp.Error
+}
+
+func TestErrorHandler(t *testing.T) {
+    tests := []struct {
+        name    string
+        err     error
+        status  int
+        code    string
+        message string
+    }{
+        {"not found", repository.NotFound("word"), http.StatusNotFound, CodeNotFound, "word not found"},
+        {"wrapped not found", fmt.Errorf("loading: %w", repository.NotFound("group")), http.StatusNotFound, CodeNotFound, "loading: group not found"},
+        {"conflict", repository.Conflict("group name already exists"), http.StatusConflict, CodeConflict, "group name already exists"},
+        {"validation", repository.Invalid("invalid word id", nil), http.StatusBadRequest, CodeValidation, "invalid word id"},
+        {"app error", AppError{Status: http.StatusTooManyRequests, Code: CodeRateLimitExceeded, Message: "too many requests"}, http.StatusTooManyRequests, CodeRateLimitExceeded, "too many requests"},
+        {"internal", errors.New("database is locked"), http.StatusInternalServerError, CodeInternal, "internal server error"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            status, body := serveError(t, tt.err)
+            assert.Equal(t, tt.status, status)
+            assert.Equal(t, tt.code, body.Code)
+            assert.Equal(t, tt.message, body.Message)
+            assert.NotNil(t, body.Details)
+        })
+    }
+}
+
+func TestErrorHandler_ValidationDetails(t *testing.T) {
+    status, body := serveError(t, repository.Invalid("japanese is required", map[string]string{"japanese": "required field"}))
+    assert.Equal(t, http.StatusBadRequest, status)
+    assert.Equal(t, map[string]interface{}{"japanese": "required field"}, body.Details)
+}
@@ -1,22 +1,85 @@
 package middleware
 
 import (
+    "crypto/rand"
+    "encoding/hex"
     "github.com/gin-gonic/gin"
+    "log/slog"
     "time"
-    "log"
+    "github.com/karl247ai/lang-portal/internal/logging"
 )
 
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds incoming request IDs so they cannot bloat the logs.
+const maxRequestIDLen = 128
+
+// RequestID tags every request with an ID, taken from the X-Request-ID
+// header when the client or a proxy sent a sane one and generated
+// otherwise. The ID is echoed in the response and the request context
+// carries logger with a request_id attribute, see logging.FromContext.
+func RequestID(logger *slog.Logger) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        id := c.GetHeader(RequestIDHeader)
+        if !validRequestID(id) {
+            id = newRequestID()
+        }
+        c.Header(RequestIDHeader, id)
+        c.Set("request_id", id)
+
+        ctx := logging.WithLogger(c.Request.Context(), logger.With("request_id", id))
+        c.Request = c.Request.WithContext(ctx)
+        c.Next()
+    }
+}
+
+func validRequestID(id string) bool {
+    if id == "" || len(id) > maxRequestIDLen {
+        return false
+    }
+    for i := 0; i < len(id); i++ {
+        if id[i] <= ' ' || id[i] > '~' {
+            return false
+        }
+    }
+    return true
+}
+
+func newRequestID() string {
+    b := make([]byte, 8)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
+
+// Logger logs every request with the request's logger: server errors at
+// error level, client errors at warn level and the rest at info level, so
+// the configured log level decides which requests show up.
 func Logger() gin.HandlerFunc {
     return func(c *gin.Context) {
         start := time.Now()
         path := c.Request.URL.Path
-        method := c.Request.Method
 
         c.Next()
 
-        latency := time.Since(start)
-        statusCode := c.Writer.Status()
-        
-        log.Printf("%s %s [%d] %v", method, path, statusCode, latency)
+        status := c.Writer.Status()
+        level := slog.LevelInfo
+        switch {
+        case status >= 500:
+            level = slog.LevelError
+        case status >= 400:
+            level = slog.LevelWarn
+        }
+
+        ctx := c.Request.Context()
+        logging.FromContext(ctx).Log(ctx, level, "request",
+            "method", c.Request.Method,
+            "path", path,
+            "route", c.FullPath(),
+            "status", status,
+            "duration_ms", float64(time.Since(start).Microseconds())/1000,
+            "bytes", c.Writer.Size(),
+            "client_ip", c.ClientIP(),
+        )
     }
-}
\ No newline at end of file
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+    "bytes"
+    "encoding/json"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "github.com/gin-gonic/gin"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/karl247ai/lang-portal/internal/logging"
+)
+
+func TestRequestIDAndLogger(t *testing.T) {
+    var buf bytes.Buffer
+    logger, err := logging.New(&buf, "info", "json")
+    require.NoError(t, err)
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.Use(RequestID(logger))
+    r.Use(Logger())
+    r.GET("/words/:id", func(c *gin.Context) {
+        logging.FromContext(c.Request.Context()).Info("handling")
+        c.Status(http.StatusNotFound)
+    })
+
+    tests := []struct {
+        name     string
+        incoming string
+        keep     bool
+    }{
+        {"incoming id kept", "client-42", true},
+        {"missing id generated", "", false},
+        {"id with spaces replaced", "bad id", false},
+        {"overlong id replaced", strings.Repeat("x", 200), false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            buf.Reset()
+            req := httptest.NewRequest(http.MethodGet, "/words/7", nil)
+            if tt.incoming != "" {
+                req.Header.Set(RequestIDHeader, tt.incoming)
+            }
+            w := httptest.NewRecorder()
+            r.ServeHTTP(w, req)
+
+            id := w.Header().Get(RequestIDHeader)
+            if tt.keep {
+                assert.Equal(t, tt.incoming, id)
+            } else {
+                assert.Len(t, id, 16)
+            }
+
+            // The handler's entry and the request entry share the ID
+            lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+            require.Len(t, lines, 2)
+            for _, line := range lines {
+                var entry map[string]interface{}
+                require.NoError(t, json.Unmarshal([]byte(line), &entry))
+                assert.Equal(t, id, entry["request_id"])
+            }
+            var entry map[string]interface{}
+            require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+            assert.Equal(t, "WARN", entry["level"])
+            assert.Equal(t, "/words/:id", entry["route"])
+            assert.Equal(t, float64(404), entry["status"])
+        })
+    }
+}
+
+func TestLogger_RecoveredPanic(t *testing.T) {
+    var buf bytes.Buffer
+    logger, err := logging.New(&buf, "info", "json")
+    require.NoError(t, err)
+
+    // The order the server uses: recovery inside the logger
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.Use(RequestID(logger))
+    r.Use(Logger())
+    r.Use(gin.RecoveryWithWriter(io.Discard))
+    r.GET("/boom", func(c *gin.Context) {
+        panic("boom")
+    })
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boom", nil))
+    assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+    var entry map[string]interface{}
+    require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+    assert.Equal(t, "ERROR", entry["level"])
+    assert.Equal(t, float64(http.StatusInternalServerError), entry["status"])
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+    "github.com/gin-gonic/gin"
+    "math"
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// bucket is a token bucket refilled continuously at the limiter's rate.
+type bucket struct {
+    tokens float64
+    last   time.Time
+}
+
+type rateLimiter struct {
+    mu      sync.Mutex
+    rate    float64 // tokens per second
+    burst   float64
+    buckets map[string]*bucket
+    swept   time.Time
+    now     func() time.Time
+}
+
+// allow takes a token from key's bucket. When the bucket is empty it
+// returns how long until the next token is available.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    now := l.now()
+    l.sweep(now)
+
+    b, ok := l.buckets[key]
+    if !ok {
+        b = &bucket{tokens: l.burst, last: now}
+        l.buckets[key] = b
+    }
+
+    b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+    b.last = now
+    if b.tokens >= 1 {
+        b.tokens--
+        return true, 0
+    }
+    return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// sweep drops buckets that have refilled completely, since they behave
+// exactly like a new bucket, so idle clients do not accumulate.
+func (l *rateLimiter) sweep(now time.Time) {
+    if now.Sub(l.swept) < time.Minute {
+        return
+    }
+    full := time.Duration(l.burst / l.rate * float64(time.Second))
+    for key, b := range l.buckets {
+        if now.Sub(b.last) >= full {
+            delete(l.buckets, key)
+        }
+    }
+    l.swept = now
+}
+
+// RateLimit allows each client IP requestsPerMinute requests on average
+// with bursts of up to burst requests. A non-positive requestsPerMinute
+// disables the limit.
+func RateLimit(requestsPerMinute, burst int) gin.HandlerFunc {
+    if requestsPerMinute <= 0 {
+        return func(c *gin.Context) { c.Next() }
+    }
+
+    limiter := &rateLimiter{
+        rate:    float64(requestsPerMinute) / 60,
+        burst:   float64(burst),
+        buckets: map[string]*bucket{},
+        now:     time.Now,
+    }
+
+    return func(c *gin.Context) {
+        ok, wait := limiter.allow(c.ClientIP())
+        if !ok {
+            retryAfter := int(math.Ceil(wait.Seconds()))
+            c.Header("Retry-After", strconv.Itoa(retryAfter))
+            c.Error(AppError{
+                Status:  http.StatusTooManyRequests,
+                Code:    CodeRateLimitExceeded,
+                Message: "too many requests",
+                Details: map[string]interface{}{"retry_after": retryAfter},
+            })
+            c.Abort()
+            return
+        }
+        c.Next()
+    }
+}
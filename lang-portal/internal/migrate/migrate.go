@@ -0,0 +1,270 @@
+// Package migrate applies the versioned SQL migrations and records them in
+// the schema_migrations table.
+package migrate
+
+import (
+    "context"
+    "crypto/sha256"
+    "database/sql"
+    "encoding/hex"
+    "fmt"
+    "io/fs"
+    "regexp"
+    "sort"
+    "strconv"
+)
+
+const createTable = `
+    CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        checksum TEXT NOT NULL,
+        applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    )
+`
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is one numbered schema change. Down is empty when the
+// migration cannot be reverted.
+type Migration struct {
+    Version  int
+    Name     string
+    Up       string
+    Down     string
+    Checksum string
+}
+
+// Status reports whether a migration has been applied.
+type Status struct {
+    Version   int    `json:"version"`
+    Name      string `json:"name"`
+    Applied   bool   `json:"applied"`
+    AppliedAt string `json:"applied_at,omitempty"`
+}
+
+// Migrator runs migrations against a database.
+type Migrator struct {
+    db         *sql.DB
+    migrations []Migration
+}
+
+// New loads the migrations found in fsys.
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+    migrations, err := Load(fsys)
+    if err != nil {
+        return nil, err
+    }
+    return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads NNN_name.up.sql and NNN_name.down.sql files from the root of
+// fsys, sorted by version. The checksum covers the up script only.
+func Load(fsys fs.FS) ([]Migration, error) {
+    entries, err := fs.ReadDir(fsys, ".")
+    if err != nil {
+        return nil, err
+    }
+
+    byVersion := map[int]*Migration{}
+    for _, entry := range entries {
+        match := fileName.FindStringSubmatch(entry.Name())
+        if entry.IsDir() || match == nil {
+            continue
+        }
+
+        version, _ := strconv.Atoi(match[1])
+        m, ok := byVersion[version]
+        if !ok {
+            m = &Migration{Version: version, Name: match[1] + "_" + match[2]}
+            byVersion[version] = m
+        } else if m.Name != match[1]+"_"+match[2] {
+            return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[1]+"_"+match[2])
+        }
+
+        body, err := fs.ReadFile(fsys, entry.Name())
+        if err != nil {
+            return nil, err
+        }
+        if match[3] == "up" {
+            m.Up = string(body)
+            sum := sha256.Sum256(body)
+            m.Checksum = hex.EncodeToString(sum[:])
+        } else {
+            m.Down = string(body)
+        }
+    }
+
+    migrations := make([]Migration, 0, len(byVersion))
+    for _, m := range byVersion {
+        if m.Up == "" {
+            return nil, fmt.Errorf("migration %s has no up script", m.Name)
+        }
+        migrations = append(migrations, *m)
+    }
+    sort.Slice(migrations, func(i, j int) bool {
+        return migrations[i].Version < migrations[j].Version
+    })
+    return migrations, nil
+}
+
+type appliedMigration struct {
+    name      string
+    checksum  string
+    appliedAt string
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+    if _, err := m.db.ExecContext(ctx, createTable); err != nil {
+        return nil, err
+    }
+
+    rows, err := m.db.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    applied := map[int]appliedMigration{}
+    for rows.Next() {
+        var version int
+        var a appliedMigration
+        if err := rows.Scan(&version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+            return nil, err
+        }
+        applied[version] = a
+    }
+    return applied, rows.Err()
+}
+
+// verify refuses to continue when an applied migration was edited after it
+// ran or no longer exists, since the schema can no longer be trusted.
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+    known := map[int]bool{}
+    for _, migration := range m.migrations {
+        known[migration.Version] = true
+        a, ok := applied[migration.Version]
+        if ok && a.checksum != migration.Checksum {
+            return fmt.Errorf("checksum mismatch for applied migration %s: database has %s, file has %s",
+                migration.Name, a.checksum, migration.Checksum)
+        }
+    }
+    for version, a := range applied {
+        if !known[version] {
+            return fmt.Errorf("applied migration %s is missing from the migration files", a.name)
+        }
+    }
+    return nil
+}
+
+// Verify checks applied migrations for checksum drift.
+func (m *Migrator) Verify(ctx context.Context) error {
+    applied, err := m.applied(ctx)
+    if err != nil {
+        return err
+    }
+    return m.verify(applied)
+}
+
+// Up applies every pending migration in order, each in its own transaction,
+// and returns the ones it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+    applied, err := m.applied(ctx)
+    if err != nil {
+        return nil, err
+    }
+    if err := m.verify(applied); err != nil {
+        return nil, err
+    }
+
+    var done []Migration
+    for _, migration := range m.migrations {
+        if _, ok := applied[migration.Version]; ok {
+            continue
+        }
+
+        err := m.inTx(ctx, func(tx *sql.Tx) error {
+            if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+                return err
+            }
+            _, err := tx.ExecContext(ctx,
+                "INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+                migration.Version, migration.Name, migration.Checksum,
+            )
+            return err
+        })
+        if err != nil {
+            return done, fmt.Errorf("apply migration %s: %w", migration.Name, err)
+        }
+        done = append(done, migration)
+    }
+    return done, nil
+}
+
+// Down reverts the latest steps applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+    applied, err := m.applied(ctx)
+    if err != nil {
+        return nil, err
+    }
+    if err := m.verify(applied); err != nil {
+        return nil, err
+    }
+
+    var done []Migration
+    for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+        migration := m.migrations[i]
+        if _, ok := applied[migration.Version]; !ok {
+            continue
+        }
+        if migration.Down == "" {
+            return done, fmt.Errorf("migration %s has no down script", migration.Name)
+        }
+
+        err := m.inTx(ctx, func(tx *sql.Tx) error {
+            if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+                return err
+            }
+            _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", migration.Version)
+            return err
+        })
+        if err != nil {
+            return done, fmt.Errorf("revert migration %s: %w", migration.Name, err)
+        }
+        done = append(done, migration)
+    }
+    return done, nil
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+    applied, err := m.applied(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    statuses := make([]Status, 0, len(m.migrations))
+    for _, migration := range m.migrations {
+        a, ok := applied[migration.Version]
+        statuses = append(statuses, Status{
+            Version:   migration.Version,
+            Name:      migration.Name,
+            Applied:   ok,
+            AppliedAt: a.appliedAt,
+        })
+    }
+    return statuses, nil
+}
+
+func (m *Migrator) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+    tx, err := m.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if err := fn(tx); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
@@ -0,0 +1,177 @@
+package migrate_test
+
+import (
+    "context"
+    "database/sql"
+    "testing"
+    "testing/fstest"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/karl247ai/lang-portal/internal/migrate"
+    "github.com/karl247ai/lang-portal/internal/testdb"
+    "github.com/karl247ai/lang-portal/migrations"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+    return testdb.Empty(t)
+}
+
+func testFS() fstest.MapFS {
+    return fstest.MapFS{
+        "001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER);")},
+        "001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+        "002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER);")},
+        "002_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+        "README.md":             {Data: []byte("ignored")},
+    }
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+    var count int
+    err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count)
+    require.NoError(t, err)
+    return count == 1
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+    db := setupTestDB(t)
+    ctx := context.Background()
+
+    m, err := migrate.New(db, testFS())
+    require.NoError(t, err)
+
+    applied, err := m.Up(ctx)
+    require.NoError(t, err)
+    assert.Len(t, applied, 2)
+    assert.True(t, tableExists(t, db, "a"))
+    assert.True(t, tableExists(t, db, "b"))
+
+    // A second run has nothing left to do
+    applied, err = m.Up(ctx)
+    require.NoError(t, err)
+    assert.Empty(t, applied)
+
+    reverted, err := m.Down(ctx, 1)
+    require.NoError(t, err)
+    require.Len(t, reverted, 1)
+    assert.Equal(t, "002_create_b", reverted[0].Name)
+    assert.False(t, tableExists(t, db, "b"))
+
+    statuses, err := m.Status(ctx)
+    require.NoError(t, err)
+    require.Len(t, statuses, 2)
+    assert.True(t, statuses[0].Applied)
+    assert.NotEmpty(t, statuses[0].AppliedAt)
+    assert.False(t, statuses[1].Applied)
+}
+
+func TestMigrator_ChecksumDrift(t *testing.T) {
+    db := setupTestDB(t)
+    ctx := context.Background()
+
+    m, err := migrate.New(db, testFS())
+    require.NoError(t, err)
+    _, err = m.Up(ctx)
+    require.NoError(t, err)
+
+    edited := testFS()
+    edited["001_create_a.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id INTEGER, name TEXT);")}
+    edited["003_create_c.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE c (id INTEGER);")}
+
+    m, err = migrate.New(db, edited)
+    require.NoError(t, err)
+
+    _, err = m.Up(ctx)
+    assert.ErrorContains(t, err, "checksum mismatch for applied migration 001_create_a")
+    assert.False(t, tableExists(t, db, "c"))
+    assert.Error(t, m.Verify(ctx))
+}
+
+func TestMigrator_FailedMigrationRollsBack(t *testing.T) {
+    db := setupTestDB(t)
+    ctx := context.Background()
+
+    fsys := testFS()
+    fsys["003_broken.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE c (id INTEGER); NOT SQL;")}
+
+    m, err := migrate.New(db, fsys)
+    require.NoError(t, err)
+
+    applied, err := m.Up(ctx)
+    assert.Error(t, err)
+    assert.Len(t, applied, 2)
+    assert.False(t, tableExists(t, db, "c"))
+
+    statuses, err := m.Status(ctx)
+    require.NoError(t, err)
+    assert.False(t, statuses[2].Applied)
+}
+
+func TestLoad_ProjectMigrations(t *testing.T) {
+    loaded, err := migrate.Load(migrations.FS)
+    require.NoError(t, err)
+    require.NotEmpty(t, loaded)
+
+    for i, m := range loaded {
+        assert.Equal(t, i+1, m.Version, "migration versions must be contiguous")
+        assert.NotEmpty(t, m.Down, "migration %s needs a down script", m.Name)
+    }
+
+    db := setupTestDB(t)
+    m, err := migrate.New(db, migrations.FS)
+    require.NoError(t, err)
+    _, err = m.Up(context.Background())
+    require.NoError(t, err)
+
+    _, err = m.Down(context.Background(), len(loaded))
+    require.NoError(t, err)
+    assert.False(t, tableExists(t, db, "words"))
+}
+
+func TestTypedWordPartsMigration(t *testing.T) {
+    db := setupTestDB(t)
+    ctx := context.Background()
+
+    m, err := migrate.New(db, migrations.FS)
+    require.NoError(t, err)
+    _, err = m.Up(ctx)
+    require.NoError(t, err)
+    // Back to the schema before 008, whatever follows it
+    loaded, err := migrate.Load(migrations.FS)
+    require.NoError(t, err)
+    reverted, err := m.Down(ctx, len(loaded)-7)
+    require.NoError(t, err)
+    require.Equal(t, "008_typed_word_parts", reverted[len(reverted)-1].Name)
+
+    _, err = db.Exec(`
+        INSERT INTO words (id, japanese, romaji, english, parts) VALUES
+            (1, '払う', 'harau', 'to pay', CAST('[{"kanji": "払", "romaji": ["ha", "ra"]}]' AS BLOB)),
+            (2, '猫', 'neko', 'cat', '{"part_of_speech": "Noun", "jlpt_level": "N5"}'),
+            (3, '犬', 'inu', 'dog', 'not json'),
+            (4, '鳥', 'tori', 'bird', '"noun"'),
+            (5, '水', 'mizu', 'water', NULL);
+    `)
+    require.NoError(t, err)
+
+    _, err = m.Up(ctx)
+    require.NoError(t, err)
+
+    parts := func(id int) interface{} {
+        var p sql.NullString
+        require.NoError(t, db.QueryRow("SELECT parts FROM words WHERE id = ?", id).Scan(&p))
+        if !p.Valid {
+            return nil
+        }
+        return p.String
+    }
+    assert.Equal(t, `{"components":[{"kanji":"払","romaji":["ha","ra"]}]}`, parts(1))
+    assert.Equal(t, `{"part_of_speech":"noun","jlpt_level":5}`, parts(2))
+    assert.Nil(t, parts(3))
+    assert.Nil(t, parts(4))
+    assert.Nil(t, parts(5))
+
+    // Reverting restores the legacy array form
+    _, err = m.Down(ctx, len(loaded)-7)
+    require.NoError(t, err)
+    assert.Equal(t, `[{"kanji":"払","romaji":["ha","ra"]}]`, parts(1))
+}
@@ -0,0 +1,15 @@
+package models
+
+// StudyProgress feeds the dashboard progress bar
+type StudyProgress struct {
+    TotalWordsStudied   int64 `json:"total_words_studied" example:"3"`
+    TotalAvailableWords int64 `json:"total_available_words" example:"124"`
+}
+
+// QuickStats is the dashboard overview of learning activity
+type QuickStats struct {
+    SuccessRate        float64 `json:"success_rate" example:"80.0"`
+    TotalStudySessions int64   `json:"total_study_sessions" example:"4"`
+    TotalActiveGroups  int64   `json:"total_active_groups" example:"3"`
+    StudyStreakDays    int     `json:"study_streak_days" example:"4"`
+}
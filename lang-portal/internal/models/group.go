@@ -0,0 +1,30 @@
+package models
+
+// Group represents a thematic collection of words
+// @Description Word group
+type Group struct {
+    ID        int64  `json:"id" example:"1"`
+    Name      string `json:"name" example:"Basic Greetings" binding:"required"`
+    WordCount int64  `json:"word_count" example:"20"`
+    CreatedAt string `json:"created_at" example:"2024-02-21T15:04:05Z07:00"`
+    UpdatedAt string `json:"updated_at" example:"2024-02-21T15:04:05Z07:00"`
+}
+
+// GroupStats holds aggregate figures for a single group
+type GroupStats struct {
+    TotalWordCount int64 `json:"total_word_count" example:"20"`
+}
+
+// GroupDetail is the single group view returned by GET /groups/:id
+type GroupDetail struct {
+    ID        int64      `json:"id" example:"1"`
+    Name      string     `json:"name" example:"Basic Greetings"`
+    Stats     GroupStats `json:"stats"`
+    CreatedAt string     `json:"created_at" example:"2024-02-21T15:04:05Z07:00"`
+    UpdatedAt string     `json:"updated_at" example:"2024-02-21T15:04:05Z07:00"`
+}
+
+// GroupWordsRequest lists the words to add to a group
+type GroupWordsRequest struct {
+    WordIDs []int64 `json:"word_ids" example:"1,2,3" binding:"required"`
+}
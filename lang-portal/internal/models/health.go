@@ -0,0 +1,29 @@
+package models
+
+// HealthReport is the body of the liveness and readiness probes
+// @Description Server health with the result of each check
+type HealthReport struct {
+    // Status is ok, or fail when any check failed
+    Status string                 `json:"status" example:"ok"`
+    Checks map[string]HealthCheck `json:"checks,omitempty"`
+    Build  BuildInfo              `json:"build"`
+    Uptime string                 `json:"uptime" example:"3h2m10s"`
+}
+
+// HealthCheck is the outcome of one readiness check
+type HealthCheck struct {
+    Status  string                 `json:"status" example:"ok"`
+    Message string                 `json:"message,omitempty" example:"2 pending migrations"`
+    Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// BuildInfo identifies the running binary
+type BuildInfo struct {
+    Version    string `json:"version" example:"1.4.0"`
+    Commit     string `json:"commit,omitempty" example:"3f2a9c1"`
+    CommitTime string `json:"commit_time,omitempty" example:"2024-02-21T15:04:05Z"`
+    // Modified is set when the binary was built from a tree with
+    // uncommitted changes
+    Modified  bool   `json:"modified,omitempty"`
+    GoVersion string `json:"go_version" example:"go1.22.1"`
+}
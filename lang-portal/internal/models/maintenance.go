@@ -0,0 +1,25 @@
+package models
+
+// Backup describes a database backup file in the backup folder
+type Backup struct {
+    Name string `json:"name" example:"langportal-pre-reset-history-20240221-150405.db"`
+    // Label tells why the backup was taken: scheduled, manual, pre-reset-history, ...
+    Label     string `json:"label" example:"pre-reset-history"`
+    SizeBytes int64  `json:"size_bytes" example:"204800"`
+    CreatedAt string `json:"created_at" example:"2024-02-21T15:04:05Z"`
+}
+
+// ResetRequest confirms a reset with the token the server issued for it
+type ResetRequest struct {
+    Confirm string `json:"confirm" example:"6f1c0e0e8a0b4d6c9b7a3e2f1d0c5b4a"`
+}
+
+// ResetResult describes a completed reset. Backup is the snapshot taken
+// just before it, from which the previous state can be restored.
+type ResetResult struct {
+    Message string           `json:"message" example:"Study history has been reset"`
+    Backup  Backup           `json:"backup"`
+    Deleted map[string]int64 `json:"deleted"`
+    // Seeded counts the words inserted by a full reset
+    Seeded int `json:"seeded,omitempty" example:"20"`
+}
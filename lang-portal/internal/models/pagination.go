@@ -5,6 +5,8 @@ type PaginationMeta struct {
     TotalPages   int   `json:"total_pages"`
     TotalItems   int64 `json:"total_items"`
     ItemsPerPage int   `json:"items_per_page"`
+    // NextCursor continues a keyset paginated listing; empty on the last page
+    NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type PaginatedResponse struct {
@@ -0,0 +1,144 @@
+package models
+
+import (
+    "bytes"
+    "database/sql/driver"
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// Parts is the structured metadata of a word, stored as JSON in the parts
+// column. Every field is optional.
+// @Description Word metadata: reading, kanji components, part of speech, JLPT level, examples and pitch accent
+type Parts struct {
+    // Reading is the kana reading of the whole word
+    Reading    string          `json:"reading,omitempty" example:"ねこ"`
+    Components []PartComponent `json:"components,omitempty"`
+    // PartOfSpeech is one of PartsOfSpeech
+    PartOfSpeech string    `json:"part_of_speech,omitempty" example:"noun"`
+    JLPTLevel    JLPTLevel `json:"jlpt_level,omitempty" swaggertype:"integer" example:"5"`
+    Examples     []Example `json:"examples,omitempty"`
+    // PitchAccent is the mora after which the pitch drops, 0 for heiban
+    // (flat) words
+    PitchAccent *int `json:"pitch_accent,omitempty" example:"1"`
+}
+
+// PartComponent is a kanji (or kana) piece of a word and how it is read
+// within the word.
+type PartComponent struct {
+    Kanji   string   `json:"kanji" example:"払"`
+    Reading string   `json:"reading,omitempty" example:"はら"`
+    Romaji  []string `json:"romaji,omitempty" example:"ha,ra"`
+}
+
+// Example is a sentence using the word.
+type Example struct {
+    Japanese string `json:"japanese" example:"猫が好きです。"`
+    English  string `json:"english" example:"I like cats."`
+}
+
+// PartsOfSpeech lists the accepted part_of_speech values.
+var PartsOfSpeech = []string{
+    "noun", "pronoun", "verb", "i-adjective", "na-adjective", "adverb",
+    "particle", "conjunction", "interjection", "counter", "prefix", "suffix",
+    "expression",
+}
+
+// JLPTLevel is a JLPT level from 5 (N5, beginner) to 1 (N1). It decodes
+// from either 5 or "N5".
+type JLPTLevel int
+
+func (l *JLPTLevel) UnmarshalJSON(data []byte) error {
+    var s string
+    if json.Unmarshal(data, &s) == nil {
+        level, err := ParseJLPTLevel(s)
+        if err != nil {
+            return err
+        }
+        *l = level
+        return nil
+    }
+
+    var n int
+    if err := json.Unmarshal(data, &n); err != nil {
+        return fmt.Errorf("jlpt_level must be a number from 1 to 5 or N1 to N5")
+    }
+    *l = JLPTLevel(n)
+    return nil
+}
+
+// ParseJLPTLevel reads a level written "5" or "N5". Range checks are left
+// to validation.
+func ParseJLPTLevel(s string) (JLPTLevel, error) {
+    n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "N"))
+    if err != nil {
+        return 0, fmt.Errorf("jlpt_level must be a number from 1 to 5 or N1 to N5")
+    }
+    return JLPTLevel(n), nil
+}
+
+// UnmarshalJSON rejects unknown keys so typos do not silently drop data,
+// and accepts the legacy form of parts, a bare array of components.
+func (p *Parts) UnmarshalJSON(data []byte) error {
+    type plain Parts
+
+    data = bytes.TrimSpace(data)
+    if len(data) > 0 && data[0] == '[' {
+        var components []PartComponent
+        if err := strictUnmarshal(data, &components); err != nil {
+            return err
+        }
+        *p = Parts{Components: components}
+        return nil
+    }
+
+    var v plain
+    if err := strictUnmarshal(data, &v); err != nil {
+        return err
+    }
+    *p = Parts(v)
+    return nil
+}
+
+func strictUnmarshal(data []byte, v interface{}) error {
+    dec := json.NewDecoder(bytes.NewReader(data))
+    dec.DisallowUnknownFields()
+    if err := dec.Decode(v); err != nil {
+        return fmt.Errorf("invalid parts: %w", err)
+    }
+    return nil
+}
+
+// Scan reads parts stored by Value. Stored keys this version does not know
+// are ignored.
+func (p *Parts) Scan(src interface{}) error {
+    type plain Parts
+
+    var data []byte
+    switch v := src.(type) {
+    case string:
+        data = []byte(v)
+    case []byte:
+        data = v
+    default:
+        return fmt.Errorf("cannot scan %T into parts", src)
+    }
+
+    var v plain
+    if err := json.Unmarshal(data, &v); err != nil {
+        return err
+    }
+    *p = Parts(v)
+    return nil
+}
+
+// Value stores parts as JSON text.
+func (p Parts) Value() (driver.Value, error) {
+    data, err := json.Marshal(p)
+    if err != nil {
+        return nil, err
+    }
+    return string(data), nil
+}
@@ -2,10 +2,93 @@ package models
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-    Error string `json:"error" example:"error message"`
+    Error ErrorBody `json:"error"`
+}
+
+// ErrorBody carries a machine-readable code, a human readable message and
+// optional details such as the invalid fields of a request
+type ErrorBody struct {
+    Code    string                 `json:"code" example:"RESOURCE_NOT_FOUND"`
+    Message string                 `json:"message" example:"word not found"`
+    Details map[string]interface{} `json:"details"`
 }
 
 // WordResponse represents a successful word operation response
 type WordResponse struct {
     Data Word `json:"data"`
-}
\ No newline at end of file
+}
+
+// WordDetailResponse represents a single word with its stats and groups
+type WordDetailResponse struct {
+    Data WordDetail `json:"data"`
+}
+
+// WordImportResponse represents the result of a bulk word import
+type WordImportResponse struct {
+    Data WordImport `json:"data"`
+}
+
+// DuplicatesResponse represents the duplicate words report
+type DuplicatesResponse struct {
+    Data []DuplicateSet `json:"data"`
+}
+
+// TransliterateResponse represents the result of a transliteration
+type TransliterateResponse struct {
+    Data Transliteration `json:"data"`
+}
+
+// GroupResponse represents a successful group operation response
+type GroupResponse struct {
+    Data Group `json:"data"`
+}
+
+// GroupDetailResponse represents a single group with its stats
+type GroupDetailResponse struct {
+    Data GroupDetail `json:"data"`
+}
+
+// StudySessionResponse represents a single study session response
+type StudySessionResponse struct {
+    Data StudySession `json:"data"`
+}
+
+// WordReviewResponse represents a recorded word review
+type WordReviewResponse struct {
+    Data WordReviewItem `json:"data"`
+}
+
+// StudyActivityResponse represents a single study activity response
+type StudyActivityResponse struct {
+    Data StudyActivity `json:"data"`
+}
+
+// StudyActivityLaunchResponse represents a launched study activity
+type StudyActivityLaunchResponse struct {
+    Data StudyActivityLaunch `json:"data"`
+}
+
+// StudyProgressResponse represents the dashboard study progress response
+type StudyProgressResponse struct {
+    Data StudyProgress `json:"data"`
+}
+
+// ResetResponse represents a completed reset
+type ResetResponse struct {
+    Data ResetResult `json:"data"`
+}
+
+// BackupResponse represents a database backup
+type BackupResponse struct {
+    Data Backup `json:"data"`
+}
+
+// BackupListResponse represents the list of database backups
+type BackupListResponse struct {
+    Data []Backup `json:"data"`
+}
+
+// QuickStatsResponse represents the dashboard quick stats response
+type QuickStatsResponse struct {
+    Data QuickStats `json:"data"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+    "time"
+)
+
+// WordSchedule is the spaced repetition state kept for a word. SM-2 works
+// off Ease and Repetitions, FSRS off Stability and Difficulty; both maintain
+// the interval, due date and lapse count.
+type WordSchedule struct {
+    WordID         int64      `json:"word_id" example:"1"`
+    Ease           float64    `json:"ease" example:"2.5"`
+    IntervalDays   float64    `json:"interval_days" example:"6"`
+    Stability      float64    `json:"stability" example:"5.8"`
+    Difficulty     float64    `json:"difficulty" example:"4.9"`
+    Repetitions    int        `json:"repetitions" example:"2"`
+    Lapses         int        `json:"lapses" example:"0"`
+    DueAt          time.Time  `json:"due_at" example:"2025-02-14T17:20:23Z"`
+    LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty" example:"2025-02-08T17:20:23Z"`
+}
+
+// DueWord is a word waiting for review. Schedule is nil for words that
+// have never been reviewed.
+type DueWord struct {
+    ID       int64         `json:"id" example:"1"`
+    Japanese string        `json:"japanese" example:"猫"`
+    Romaji   string        `json:"romaji" example:"neko"`
+    English  string        `json:"english" example:"cat"`
+    Schedule *WordSchedule `json:"schedule"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// StudyActivity is a learning app that can be launched against a word group
+// @Description Study activity
+type StudyActivity struct {
+    ID           int64  `json:"id" example:"1"`
+    Name         string `json:"name" example:"Vocabulary Quiz"`
+    ThumbnailURL string `json:"thumbnail_url" example:"https://example.com/thumbnail.jpg"`
+    Description  string `json:"description" example:"Practice your vocabulary with flashcards"`
+    LaunchURL    string `json:"launch_url" example:"http://localhost:8081"`
+    CreatedAt    string `json:"created_at" example:"2024-02-21T15:04:05Z07:00"`
+}
+
+// StudyActivityLaunch is returned when a study activity is started for a group
+type StudyActivityLaunch struct {
+    ID              int64  `json:"id" example:"124"`
+    GroupID         int64  `json:"group_id" example:"123"`
+    StudyActivityID int64  `json:"study_activity_id" example:"1"`
+    LaunchURL       string `json:"launch_url" example:"http://localhost:8081?group_id=123&study_session_id=124"`
+}
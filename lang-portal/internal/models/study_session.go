@@ -0,0 +1,60 @@
+package models
+
+// StudySession represents one sitting of a study activity against a group
+// @Description Study session with its review totals
+type StudySession struct {
+    ID               int64  `json:"id" example:"123"`
+    GroupID          int64  `json:"group_id" example:"456"`
+    GroupName        string `json:"group_name" example:"Basic Greetings"`
+    StudyActivityID  int64  `json:"study_activity_id" example:"789"`
+    ActivityName     string `json:"activity_name" example:"Vocabulary Quiz"`
+    StartTime        string `json:"start_time" example:"2025-02-08T17:20:23-05:00"`
+    EndTime          string `json:"end_time" example:"2025-02-08T17:30:23-05:00"`
+    ReviewItemsCount int64  `json:"review_items_count" example:"20"`
+    CorrectCount     int64  `json:"correct_count" example:"15"`
+    WrongCount       int64  `json:"wrong_count" example:"5"`
+}
+
+// CreateStudySessionRequest starts a session for a group and activity
+type CreateStudySessionRequest struct {
+    GroupID         int64 `json:"group_id" example:"456" binding:"required"`
+    StudyActivityID int64 `json:"study_activity_id" example:"789" binding:"required"`
+}
+
+// RecordStudySessionRequest creates a session together with answers already
+// given in it, such as by an activity that ran offline
+type RecordStudySessionRequest struct {
+    CreateStudySessionRequest
+    Reviews []SessionReview `json:"reviews,omitempty" binding:"dive"`
+}
+
+// SessionReview is one answer of a RecordStudySessionRequest
+type SessionReview struct {
+    WordID  int64 `json:"word_id" example:"1" binding:"required"`
+    Correct *bool `json:"correct" example:"true" binding:"required"`
+}
+
+// WordReviewItem records a single answer given during a study session
+// @Description Word review result
+type WordReviewItem struct {
+    ID             int64  `json:"id" example:"1"`
+    WordID         int64  `json:"word_id" example:"1"`
+    StudySessionID int64  `json:"study_session_id" example:"123"`
+    Correct        bool   `json:"correct" example:"true"`
+    CreatedAt      string `json:"created_at" example:"2025-02-08T17:33:07-05:00"`
+}
+
+// ReviewRequest is the payload of POST /study_sessions/:id/words/:word_id/review
+type ReviewRequest struct {
+    Correct *bool `json:"correct" example:"true" binding:"required"`
+}
+
+// ReviewedWord is a word together with its correct/wrong tallies
+type ReviewedWord struct {
+    ID           int64  `json:"id" example:"1"`
+    Japanese     string `json:"japanese" example:"こんにちは"`
+    Romaji       string `json:"romaji" example:"konnichiwa"`
+    English      string `json:"english" example:"hello"`
+    CorrectCount int64  `json:"correct_count" example:"5"`
+    WrongCount   int64  `json:"wrong_count" example:"2"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// TransliterateRequest asks for the romaji of a kana text
+type TransliterateRequest struct {
+    Text string `json:"text" example:"とうきょう" binding:"required"`
+    // System is hepburn (the default), kunrei or nihon-shiki
+    System  string `json:"system" example:"hepburn"`
+    Macrons bool   `json:"macrons" example:"false"`
+}
+
+// Transliteration is the romaji of a kana text
+type Transliteration struct {
+    Text    string `json:"text" example:"とうきょう"`
+    System  string `json:"system" example:"hepburn"`
+    Macrons bool   `json:"macrons" example:"false"`
+    Romaji  string `json:"romaji" example:"toukyou"`
+}
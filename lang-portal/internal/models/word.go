@@ -1,8 +1,8 @@
 package models
 
 import (
-    "time"
-    "encoding/json"
+    "fmt"
+    "strings"
 )
 
 // Word represents a vocabulary item
@@ -10,9 +10,104 @@ import (
 type Word struct {
     ID        int64  `json:"id" example:"1"`
     Japanese  string `json:"japanese" example:"猫" binding:"required"`
-    Romaji    string `json:"romaji" example:"neko" binding:"required"`
+    Romaji    string `json:"romaji" example:"neko"`
     English   string `json:"english" example:"cat" binding:"required"`
-    Parts     json.RawMessage `json:"parts,omitempty"`
+    Parts     *Parts `json:"parts,omitempty"`
     CreatedAt string `json:"created_at" example:"2024-02-21T15:04:05Z07:00"`
     UpdatedAt string `json:"updated_at" example:"2024-02-21T15:04:05Z07:00"`
-}
\ No newline at end of file
+}
+
+// WordStats holds the review totals of a single word
+type WordStats struct {
+    CorrectCount int64 `json:"correct_count" example:"5"`
+    WrongCount   int64 `json:"wrong_count" example:"2"`
+}
+
+// WordGroup is a group a word belongs to
+type WordGroup struct {
+    ID   int64  `json:"id" example:"1"`
+    Name string `json:"name" example:"Basic Greetings"`
+}
+
+// WordDetail is the single word view returned by GET /words/:id
+type WordDetail struct {
+    Word
+    Stats  WordStats   `json:"stats"`
+    Groups []WordGroup `json:"groups"`
+}
+
+// WordImport summarises a successful bulk import. Imported counts every
+// row; Updated counts the rows that updated an existing duplicate word
+// instead of creating one.
+type WordImport struct {
+    Format   string `json:"format" example:"csv"`
+    Imported int    `json:"imported" example:"120"`
+    Updated  int    `json:"updated" example:"3"`
+    GroupID  int64  `json:"group_id,omitempty" example:"1"`
+}
+
+// DuplicatePolicy decides what happens when a word is saved with the same
+// normalized japanese and english as an existing word.
+type DuplicatePolicy string
+
+const (
+    // DuplicateReject refuses the word with a conflict error
+    DuplicateReject DuplicatePolicy = "reject"
+    // DuplicateUpsert updates the existing word instead of creating one
+    DuplicateUpsert DuplicatePolicy = "upsert"
+    // DuplicateAllow saves the word anyway
+    DuplicateAllow DuplicatePolicy = "allow"
+)
+
+// DuplicatePolicies lists the accepted policies, the default first.
+var DuplicatePolicies = []DuplicatePolicy{DuplicateReject, DuplicateUpsert, DuplicateAllow}
+
+// ParseDuplicatePolicy validates a policy name.
+func ParseDuplicatePolicy(name string) (DuplicatePolicy, error) {
+    names := make([]string, len(DuplicatePolicies))
+    for i, p := range DuplicatePolicies {
+        if string(p) == name {
+            return p, nil
+        }
+        names[i] = string(p)
+    }
+    return "", fmt.Errorf("unknown duplicate policy %q, must be one of %s", name, strings.Join(names, ", "))
+}
+
+// DuplicateSet is a group of words that normalize to the same japanese and
+// english text: widths folded, katakana as hiragana, case and spacing
+// ignored.
+type DuplicateSet struct {
+    // Japanese and English are the shared normalized text
+    Japanese string `json:"japanese" example:"ねこ"`
+    English  string `json:"english" example:"cat"`
+    Words    []Word `json:"words"`
+}
+
+// WordMergeRequest lists the words to fold into another word
+type WordMergeRequest struct {
+    WordIDs []int64 `json:"word_ids" example:"2,3" binding:"required"`
+}
+
+// WordFilter narrows a word listing. Zero values disable a filter.
+type WordFilter struct {
+    // Query is free text matched against japanese, romaji, english and
+    // the text inside parts. Every term must match.
+    Query string
+    // Prefix makes each query term match words starting with it.
+    Prefix  bool
+    GroupID int64
+    // The remaining filters match fields of Parts
+    PartOfSpeech string
+    JLPTLevel    JLPTLevel
+    Kanji        string
+    HasExamples  *bool
+    PitchAccent  *int
+}
+
+// WordSort orders a word listing. An empty Field orders by relevance when
+// the filter has a text query and by id otherwise.
+type WordSort struct {
+    Field string
+    Desc  bool
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+    "database/sql"
+    "context"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "time"
+)
+
+type DashboardRepository struct {
+    db *sql.DB
+}
+
+func NewDashboardRepository(db *sql.DB) *DashboardRepository {
+    return &DashboardRepository{db: db}
+}
+
+func (r *DashboardRepository) GetLastStudySession(ctx context.Context) (*models.StudySession, error) {
+    ctx, done := observe(ctx, "dashboard", "GetLastStudySession")
+    defer done()
+
+    query := sessionQuery + `
+        GROUP BY s.id
+        ORDER BY s.created_at DESC, s.id DESC
+        LIMIT 1`
+
+    var s models.StudySession
+    err := scanSession(r.db.QueryRowContext(ctx, query), &s)
+    if err == sql.ErrNoRows {
+        return nil, NotFound("study session")
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &s, nil
+}
+
+func (r *DashboardRepository) GetStudyProgress(ctx context.Context) (*models.StudyProgress, error) {
+    ctx, done := observe(ctx, "dashboard", "GetStudyProgress")
+    defer done()
+
+    query := `SELECT (SELECT COUNT(DISTINCT word_id) FROM word_review_items),
+                     (SELECT COUNT(*) FROM words)`
+
+    var p models.StudyProgress
+    if err := r.db.QueryRowContext(ctx, query).Scan(&p.TotalWordsStudied, &p.TotalAvailableWords); err != nil {
+        return nil, err
+    }
+    return &p, nil
+}
+
+// GetQuickStats fills every quick stat except the streak, which depends on
+// the caller's time zone and is computed from GetLatestReviewBefore.
+func (r *DashboardRepository) GetQuickStats(ctx context.Context) (*models.QuickStats, error) {
+    ctx, done := observe(ctx, "dashboard", "GetQuickStats")
+    defer done()
+
+    query := `SELECT (SELECT COUNT(*) FROM word_review_items),
+                     (SELECT COUNT(*) FROM word_review_items WHERE correct),
+                     (SELECT COUNT(*) FROM study_sessions),
+                     (SELECT COUNT(DISTINCT group_id) FROM study_sessions)`
+
+    var reviews, correct int64
+    var stats models.QuickStats
+    err := r.db.QueryRowContext(ctx, query).Scan(
+        &reviews, &correct, &stats.TotalStudySessions, &stats.TotalActiveGroups,
+    )
+    if err != nil {
+        return nil, err
+    }
+
+    if reviews > 0 {
+        stats.SuccessRate = float64(correct) * 100 / float64(reviews)
+    }
+    return &stats, nil
+}
+
+// GetLatestReviewBefore returns the time of the newest review recorded
+// strictly before t. The bool is false when there is none.
+func (r *DashboardRepository) GetLatestReviewBefore(ctx context.Context, t time.Time) (time.Time, bool, error) {
+    ctx, done := observe(ctx, "dashboard", "GetLatestReviewBefore")
+    defer done()
+
+    var latest sql.NullString
+    err := r.db.QueryRowContext(ctx,
+        "SELECT MAX(created_at) FROM word_review_items WHERE created_at < ?",
+        t.UTC().Format("2006-01-02 15:04:05"),
+    ).Scan(&latest)
+    if err != nil || !latest.Valid {
+        return time.Time{}, false, err
+    }
+
+    ts, err := parseTimestamp(latest.String)
+    if err != nil {
+        return time.Time{}, false, err
+    }
+    return ts, true, nil
+}
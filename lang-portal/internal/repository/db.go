@@ -1,12 +1,18 @@
 package repository
 
 import (
+    "context"
     "database/sql"
+    "errors"
+    "fmt"
     _ "github.com/mattn/go-sqlite3"
 )
 
-func NewDB() (*sql.DB, error) {
-    db, err := sql.Open("sqlite3", "./langportal.db")
+// OpenDB opens the SQLite database at path with foreign keys enforced. The
+// database uses a write-ahead log so reads are not blocked by a writer;
+// close it with CloseDB to fold the log back into the database file.
+func OpenDB(path string) (*sql.DB, error) {
+    db, err := sql.Open("sqlite3", path+"?_foreign_keys=on&_journal_mode=WAL")
     if err != nil {
         return nil, err
     }
@@ -14,6 +20,95 @@ func NewDB() (*sql.DB, error) {
     if err = db.Ping(); err != nil {
         return nil, err
     }
+
+    // Word search needs FTS5, which go-sqlite3 only builds with a tag
+    var fts5 bool
+    if err = db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5); err != nil {
+        return nil, err
+    }
+    if !fts5 {
+        db.Close()
+        return nil, errors.New("SQLite was built without FTS5, rebuild with -tags sqlite_fts5")
+    }
     
     return db, nil
-}
\ No newline at end of file
+}
+
+// CloseDB checkpoints the write-ahead log into the database file, leaving
+// the file complete on its own, and closes db. db is closed even when the
+// checkpoint fails.
+func CloseDB(db *sql.DB) error {
+    var busy, logPages, checkpointed int
+    err := db.QueryRow("PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logPages, &checkpointed)
+    if err == nil && busy != 0 {
+        err = errors.New("checkpoint blocked by an open transaction")
+    }
+    if cerr := db.Close(); err == nil {
+        err = cerr
+    }
+    return err
+}
+
+// DBTX is the part of *sql.DB and *sql.Tx the repositories query through,
+// so the same repository code runs on its own or inside a unit of work.
+type DBTX interface {
+    ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+    QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+    QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+    PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// txScope is a transaction begun by beginTx. Inside a surrounding
+// transaction it is a savepoint: Rollback then only undoes the writes made
+// since beginTx and Commit leaves the outcome to the owner of the
+// transaction.
+type txScope struct {
+    *sql.Tx
+    savepoint bool
+    done      bool
+}
+
+// beginTx begins a transaction on db, or a savepoint when db is already a
+// transaction, as in Store.WithTx.
+func beginTx(ctx context.Context, db DBTX) (*txScope, error) {
+    switch db := db.(type) {
+    case *sql.DB:
+        tx, err := db.BeginTx(ctx, nil)
+        if err != nil {
+            return nil, err
+        }
+        return &txScope{Tx: tx}, nil
+    case *sql.Tx:
+        if _, err := db.ExecContext(ctx, "SAVEPOINT repository"); err != nil {
+            return nil, err
+        }
+        return &txScope{Tx: db, savepoint: true}, nil
+    default:
+        return nil, fmt.Errorf("cannot begin a transaction on %T", db)
+    }
+}
+
+func (t *txScope) Commit() error {
+    if !t.savepoint {
+        return t.Tx.Commit()
+    }
+    if t.done {
+        return sql.ErrTxDone
+    }
+    t.done = true
+    _, err := t.Tx.Exec("RELEASE repository")
+    return err
+}
+
+func (t *txScope) Rollback() error {
+    if !t.savepoint {
+        return t.Tx.Rollback()
+    }
+    if t.done {
+        return sql.ErrTxDone
+    }
+    t.done = true
+    // Savepoints nest by name, so this reaches the one beginTx opened
+    _, err := t.Tx.Exec("ROLLBACK TO repository; RELEASE repository")
+    return err
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+    "testing"
+    "os"
+    "path/filepath"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestCloseDB_TruncatesLog(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "words.db")
+    db, err := OpenDB(path)
+    require.NoError(t, err)
+
+    // A second connection keeps SQLite from folding the log back on its own
+    // when db closes, so only the checkpoint can empty it
+    other, err := OpenDB(path)
+    require.NoError(t, err)
+    defer other.Close()
+
+    _, err = db.Exec(`
+        CREATE TABLE notes (body TEXT);
+        INSERT INTO notes (body) VALUES ('猫'), ('犬');
+    `)
+    require.NoError(t, err)
+    info, err := os.Stat(path + "-wal")
+    require.NoError(t, err)
+    require.NotZero(t, info.Size())
+
+    require.NoError(t, CloseDB(db))
+    info, err = os.Stat(path + "-wal")
+    require.NoError(t, err)
+    assert.Zero(t, info.Size())
+    assert.Error(t, db.Ping(), "db is closed")
+
+    var n int
+    require.NoError(t, other.QueryRow("SELECT COUNT(*) FROM notes").Scan(&n))
+    assert.Equal(t, 2, n)
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+    "errors"
+)
+
+// Sentinel error kinds. Use errors.Is to test for them; the concrete error
+// carries a specific message such as "word not found".
+var (
+    ErrNotFound   = errors.New("not found")
+    ErrConflict   = errors.New("conflict")
+    ErrValidation = errors.New("validation failed")
+)
+
+// Error is an error of one of the sentinel kinds. Details holds extra
+// machine-readable context, e.g. the invalid fields of a request.
+type Error struct {
+    Kind    error
+    Message string
+    Details map[string]string
+}
+
+func (e *Error) Error() string {
+    return e.Message
+}
+
+func (e *Error) Unwrap() error {
+    return e.Kind
+}
+
+// NotFound reports that the named resource does not exist.
+func NotFound(resource string) error {
+    return &Error{Kind: ErrNotFound, Message: resource + " not found"}
+}
+
+// Conflict reports a write that clashes with existing data.
+func Conflict(message string) error {
+    return &Error{Kind: ErrConflict, Message: message}
+}
+
+// Invalid reports invalid input, optionally with a message per field.
+func Invalid(message string, fields map[string]string) error {
+    return &Error{Kind: ErrValidation, Message: message, Details: fields}
+}
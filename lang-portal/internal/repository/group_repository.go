@@ -0,0 +1,260 @@
+package repository
+
+import (
+    "database/sql"
+    "context"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "strings"
+)
+
+type GroupRepository struct {
+    db DBTX
+}
+
+func NewGroupRepository(db *sql.DB) *GroupRepository {
+    return &GroupRepository{db: db}
+}
+
+func (r *GroupRepository) GetGroups(ctx context.Context, limit, offset int) ([]models.Group, error) {
+    ctx, done := observe(ctx, "groups", "GetGroups")
+    defer done()
+
+    query := `SELECT g.id, g.name, COUNT(wg.word_id), g.created_at, g.updated_at
+              FROM groups g
+              LEFT JOIN words_groups wg ON wg.group_id = g.id
+              GROUP BY g.id
+              ORDER BY g.name
+              LIMIT ? OFFSET ?`
+
+    rows, err := r.db.QueryContext(ctx, query, limit, offset)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    groups := []models.Group{}
+    for rows.Next() {
+        var g models.Group
+        if err := rows.Scan(&g.ID, &g.Name, &g.WordCount, &g.CreatedAt, &g.UpdatedAt); err != nil {
+            return nil, err
+        }
+        groups = append(groups, g)
+    }
+    return groups, rows.Err()
+}
+
+func (r *GroupRepository) GetGroupsCount(ctx context.Context) (int64, error) {
+    ctx, done := observe(ctx, "groups", "GetGroupsCount")
+    defer done()
+
+    var count int64
+    err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM groups").Scan(&count)
+    return count, err
+}
+
+func (r *GroupRepository) GetGroup(ctx context.Context, id int64) (*models.GroupDetail, error) {
+    ctx, done := observe(ctx, "groups", "GetGroup")
+    defer done()
+
+    query := `SELECT g.id, g.name, g.created_at, g.updated_at,
+                     (SELECT COUNT(*) FROM words_groups wg WHERE wg.group_id = g.id)
+              FROM groups g
+              WHERE g.id = ?`
+
+    var g models.GroupDetail
+    err := r.db.QueryRowContext(ctx, query, id).Scan(
+        &g.ID, &g.Name, &g.CreatedAt, &g.UpdatedAt, &g.Stats.TotalWordCount,
+    )
+    if err == sql.ErrNoRows {
+        return nil, NotFound("group")
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &g, nil
+}
+
+func (r *GroupRepository) CreateGroup(ctx context.Context, group *models.Group) error {
+    ctx, done := observe(ctx, "groups", "CreateGroup")
+    defer done()
+
+    query := `
+        INSERT INTO groups (name, created_at, updated_at)
+        VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+
+    result, err := r.db.ExecContext(ctx, query, group.Name)
+    if err != nil {
+        return groupWriteError(err)
+    }
+
+    id, err := result.LastInsertId()
+    if err != nil {
+        return err
+    }
+
+    group.ID = id
+    return nil
+}
+
+func (r *GroupRepository) UpdateGroup(ctx context.Context, id int64, group *models.Group) error {
+    ctx, done := observe(ctx, "groups", "UpdateGroup")
+    defer done()
+
+    query := `
+        UPDATE groups
+        SET name = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+
+    result, err := r.db.ExecContext(ctx, query, group.Name, id)
+    if err != nil {
+        return groupWriteError(err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return NotFound("group")
+    }
+
+    group.ID = id
+    return nil
+}
+
+func (r *GroupRepository) DeleteGroup(ctx context.Context, id int64) error {
+    ctx, done := observe(ctx, "groups", "DeleteGroup")
+    defer done()
+
+    result, err := r.db.ExecContext(ctx, "DELETE FROM groups WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return NotFound("group")
+    }
+
+    return nil
+}
+
+func (r *GroupRepository) GetGroupWords(ctx context.Context, groupID int64, limit, offset int) ([]models.Word, error) {
+    ctx, done := observe(ctx, "groups", "GetGroupWords")
+    defer done()
+
+    query := `SELECT w.id, w.japanese, w.romaji, w.english, w.parts, w.created_at, w.updated_at
+              FROM words w
+              JOIN words_groups wg ON wg.word_id = w.id
+              WHERE wg.group_id = ?
+              ORDER BY w.id
+              LIMIT ? OFFSET ?`
+
+    rows, err := r.db.QueryContext(ctx, query, groupID, limit, offset)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    words := []models.Word{}
+    for rows.Next() {
+        var w models.Word
+        if err := scanWord(rows, &w); err != nil {
+            return nil, err
+        }
+        words = append(words, w)
+    }
+    return words, rows.Err()
+}
+
+func (r *GroupRepository) GetGroupWordsCount(ctx context.Context, groupID int64) (int64, error) {
+    ctx, done := observe(ctx, "groups", "GetGroupWordsCount")
+    defer done()
+
+    var count int64
+    err := r.db.QueryRowContext(ctx,
+        "SELECT COUNT(*) FROM words_groups WHERE group_id = ?", groupID,
+    ).Scan(&count)
+    return count, err
+}
+
+// AddWords links the given words to a group. Words that already belong to
+// the group are left untouched, so the call is safe to repeat.
+func (r *GroupRepository) AddWords(ctx context.Context, groupID int64, wordIDs []int64) error {
+    ctx, done := observe(ctx, "groups", "AddWords")
+    defer done()
+
+    tx, err := beginTx(ctx, r.db)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if err := requireRow(ctx, tx, "SELECT 1 FROM groups WHERE id = ?", groupID, "group"); err != nil {
+        return err
+    }
+
+    for _, wordID := range wordIDs {
+        if err := requireRow(ctx, tx, "SELECT 1 FROM words WHERE id = ?", wordID, "word"); err != nil {
+            return err
+        }
+
+        _, err := tx.ExecContext(ctx, `
+            INSERT OR IGNORE INTO words_groups (word_id, group_id, created_at)
+            VALUES (?, ?, CURRENT_TIMESTAMP)
+        `, wordID, groupID)
+        if err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+func (r *GroupRepository) RemoveWord(ctx context.Context, groupID, wordID int64) error {
+    ctx, done := observe(ctx, "groups", "RemoveWord")
+    defer done()
+
+    result, err := r.db.ExecContext(ctx,
+        "DELETE FROM words_groups WHERE group_id = ? AND word_id = ?", groupID, wordID,
+    )
+    if err != nil {
+        return err
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+
+    if rowsAffected == 0 {
+        return &Error{Kind: ErrNotFound, Message: "word not in group"}
+    }
+
+    return nil
+}
+
+// groupWriteError translates the UNIQUE(name) violation into a readable error.
+func groupWriteError(err error) error {
+    if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+        return Conflict("group name already exists")
+    }
+    return err
+}
+
+// requireRow returns a not found error for resource when query yields no rows.
+func requireRow(ctx context.Context, tx DBTX, query string, id int64, resource string) error {
+    var one int
+    err := tx.QueryRowContext(ctx, query, id).Scan(&one)
+    if err == sql.ErrNoRows {
+        return NotFound(resource)
+    }
+    return err
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+    "testing"
+    "context"
+    "github.com/stretchr/testify/assert"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+func TestGroupRepository_Membership(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    _, err := db.Exec(`
+        INSERT INTO words (japanese, romaji, english)
+        VALUES ('こんにちは', 'konnichiwa', 'hello'), ('さようなら', 'sayounara', 'goodbye')
+    `)
+    assert.NoError(t, err)
+
+    ctx := context.Background()
+    repo := NewGroupRepository(db)
+
+    group := models.Group{Name: "Basic Greetings"}
+    assert.NoError(t, repo.CreateGroup(ctx, &group))
+    assert.NotZero(t, group.ID)
+
+    // Adding the same word twice must not create a second membership
+    assert.NoError(t, repo.AddWords(ctx, group.ID, []int64{1, 2}))
+    assert.NoError(t, repo.AddWords(ctx, group.ID, []int64{1}))
+
+    groups, err := repo.GetGroups(ctx, 10, 0)
+    assert.NoError(t, err)
+    assert.Len(t, groups, 1)
+    assert.Equal(t, int64(2), groups[0].WordCount)
+
+    detail, err := repo.GetGroup(ctx, group.ID)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(2), detail.Stats.TotalWordCount)
+
+    words, err := repo.GetGroupWords(ctx, group.ID, 10, 0)
+    assert.NoError(t, err)
+    assert.Len(t, words, 2)
+    assert.Equal(t, "hello", words[0].English)
+
+    assert.NoError(t, repo.RemoveWord(ctx, group.ID, 1))
+    assert.EqualError(t, repo.RemoveWord(ctx, group.ID, 1), "word not in group")
+
+    count, err := repo.GetGroupWordsCount(ctx, group.ID)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(1), count)
+}
+
+func TestGroupRepository_Errors(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    ctx := context.Background()
+    repo := NewGroupRepository(db)
+
+    assert.NoError(t, repo.CreateGroup(ctx, &models.Group{Name: "Verbs"}))
+    err := repo.CreateGroup(ctx, &models.Group{Name: "Verbs"})
+    assert.EqualError(t, err, "group name already exists")
+    assert.ErrorIs(t, err, ErrConflict)
+
+    _, err = repo.GetGroup(ctx, 999)
+    assert.EqualError(t, err, "group not found")
+    assert.ErrorIs(t, err, ErrNotFound)
+
+    assert.EqualError(t, repo.AddWords(ctx, 999, []int64{1}), "group not found")
+    assert.EqualError(t, repo.AddWords(ctx, 1, []int64{999}), "word not found")
+    assert.EqualError(t, repo.DeleteGroup(ctx, 999), "group not found")
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+)
+
+// historyTables hold the study history, children first.
+var historyTables = []string{"word_review_items", "word_schedules", "study_sessions"}
+
+// contentTables hold the vocabulary, children first. Study activities are
+// configuration rather than content and survive a full reset.
+var contentTables = []string{"words_groups", "words", "groups"}
+
+// MaintenanceRepository clears data in bulk for the settings page resets.
+type MaintenanceRepository struct {
+    db *sql.DB
+}
+
+func NewMaintenanceRepository(db *sql.DB) *MaintenanceRepository {
+    return &MaintenanceRepository{db: db}
+}
+
+// ResetHistory deletes every study session, review and review schedule in
+// one transaction, keeping words and groups. It returns the number of rows
+// deleted per table.
+func (r *MaintenanceRepository) ResetHistory(ctx context.Context) (map[string]int64, error) {
+    ctx, done := observe(ctx, "maintenance", "ResetHistory")
+    defer done()
+
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    deleted, err := clearTables(ctx, tx, historyTables)
+    if err != nil {
+        return nil, err
+    }
+    return deleted, tx.Commit()
+}
+
+// FullReset deletes all study history and vocabulary, then calls seed to
+// fill the emptied database, all in one transaction: if seeding fails
+// nothing is deleted. It returns the number of rows deleted per table.
+func (r *MaintenanceRepository) FullReset(ctx context.Context, seed func(context.Context, *sql.Tx) error) (map[string]int64, error) {
+    ctx, done := observe(ctx, "maintenance", "FullReset")
+    defer done()
+
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    deleted, err := clearTables(ctx, tx, append(append([]string{}, historyTables...), contentTables...))
+    if err != nil {
+        return nil, err
+    }
+    if err := seed(ctx, tx); err != nil {
+        return nil, err
+    }
+    return deleted, tx.Commit()
+}
+
+// clearTables deletes every row of tables and restarts their ids at 1.
+func clearTables(ctx context.Context, tx *sql.Tx, tables []string) (map[string]int64, error) {
+    deleted := map[string]int64{}
+    for _, table := range tables {
+        result, err := tx.ExecContext(ctx, "DELETE FROM "+table)
+        if err != nil {
+            return nil, err
+        }
+        if deleted[table], err = result.RowsAffected(); err != nil {
+            return nil, err
+        }
+        // Only AUTOINCREMENT tables have a sequence
+        if _, err := tx.ExecContext(ctx, "DELETE FROM sqlite_sequence WHERE name = ?", table); err != nil {
+            return nil, err
+        }
+    }
+    return deleted, nil
+}
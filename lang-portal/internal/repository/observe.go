@@ -0,0 +1,55 @@
+package repository
+
+import (
+    "context"
+)
+
+// QueryObserver watches repository methods, for metrics, logs or traces.
+// ObserveQuery is called as a method starts and returns the context the
+// method runs with and a function called when it returns.
+type QueryObserver interface {
+    ObserveQuery(ctx context.Context, repository, method string) (context.Context, func())
+}
+
+var observers []QueryObserver
+
+// Observe adds o to the observers of every repository method. Observers
+// are meant to be added at startup, before any repository is used.
+func Observe(o QueryObserver) {
+    observers = append(observers, o)
+}
+
+// observe notifies the observers that method of repository starts. The
+// returned function must be called when the method returns.
+func observe(ctx context.Context, repository, method string) (context.Context, func()) {
+    if len(observers) == 0 {
+        return ctx, func() {}
+    }
+
+    dones := make([]func(), len(observers))
+    for i, o := range observers {
+        ctx, dones[i] = o.ObserveQuery(ctx, repository, method)
+    }
+    return ctx, func() {
+        for i := len(dones) - 1; i >= 0; i-- {
+            dones[i]()
+        }
+    }
+}
+
+// RowObserver is a QueryObserver that also wants to know how many rows a
+// repository method read or wrote.
+type RowObserver interface {
+    QueryObserver
+    ObserveRows(ctx context.Context, rows int64)
+}
+
+// observeRows reports the rows a method read or wrote. ctx must be the
+// context returned by observe for that method.
+func observeRows(ctx context.Context, rows int) {
+    for _, o := range observers {
+        if ro, ok := o.(RowObserver); ok {
+            ro.ObserveRows(ctx, int64(rows))
+        }
+    }
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+    "database/sql"
+    "context"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "time"
+)
+
+type ScheduleRepository struct {
+    db DBTX
+}
+
+func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+    return &ScheduleRepository{db: db}
+}
+
+// sqliteTime formats t the way CURRENT_TIMESTAMP does so that stored values
+// compare correctly as text.
+func sqliteTime(t time.Time) string {
+    return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// GetSchedule returns the schedule of a word, or a fresh zero schedule when
+// the word has not been reviewed yet.
+func (r *ScheduleRepository) GetSchedule(ctx context.Context, wordID int64) (*models.WordSchedule, error) {
+    ctx, done := observe(ctx, "schedules", "GetSchedule")
+    defer done()
+
+    query := `SELECT word_id, ease, interval_days, stability, difficulty, repetitions, lapses, due_at, last_reviewed_at
+              FROM word_schedules
+              WHERE word_id = ?`
+
+    var s models.WordSchedule
+    var lastReviewed sql.NullTime
+    err := r.db.QueryRowContext(ctx, query, wordID).Scan(
+        &s.WordID, &s.Ease, &s.IntervalDays, &s.Stability, &s.Difficulty,
+        &s.Repetitions, &s.Lapses, &s.DueAt, &lastReviewed,
+    )
+    if err == sql.ErrNoRows {
+        return &models.WordSchedule{WordID: wordID}, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    if lastReviewed.Valid {
+        s.LastReviewedAt = &lastReviewed.Time
+    }
+    return &s, nil
+}
+
+func (r *ScheduleRepository) SaveSchedule(ctx context.Context, s *models.WordSchedule) error {
+    ctx, done := observe(ctx, "schedules", "SaveSchedule")
+    defer done()
+
+    query := `
+        INSERT INTO word_schedules (word_id, ease, interval_days, stability, difficulty, repetitions, lapses, due_at, last_reviewed_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (word_id) DO UPDATE SET
+            ease = excluded.ease,
+            interval_days = excluded.interval_days,
+            stability = excluded.stability,
+            difficulty = excluded.difficulty,
+            repetitions = excluded.repetitions,
+            lapses = excluded.lapses,
+            due_at = excluded.due_at,
+            last_reviewed_at = excluded.last_reviewed_at
+    `
+
+    var lastReviewed interface{}
+    if s.LastReviewedAt != nil {
+        lastReviewed = sqliteTime(*s.LastReviewedAt)
+    }
+
+    _, err := r.db.ExecContext(ctx, query,
+        s.WordID, s.Ease, s.IntervalDays, s.Stability, s.Difficulty,
+        s.Repetitions, s.Lapses, sqliteTime(s.DueAt), lastReviewed,
+    )
+    return err
+}
+
+// GetDueWords returns up to limit words due at now, most overdue first,
+// followed by words that have never been reviewed.
+func (r *ScheduleRepository) GetDueWords(ctx context.Context, now time.Time, limit int) ([]models.DueWord, error) {
+    ctx, done := observe(ctx, "schedules", "GetDueWords")
+    defer done()
+
+    query := `SELECT w.id, w.japanese, w.romaji, w.english,
+                     s.word_id, s.ease, s.interval_days, s.stability, s.difficulty,
+                     s.repetitions, s.lapses, s.due_at, s.last_reviewed_at
+              FROM words w
+              LEFT JOIN word_schedules s ON s.word_id = w.id
+              WHERE s.word_id IS NULL OR s.due_at <= ?
+              ORDER BY s.word_id IS NULL, s.due_at, w.id
+              LIMIT ?`
+
+    rows, err := r.db.QueryContext(ctx, query, sqliteTime(now), limit)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    words := []models.DueWord{}
+    for rows.Next() {
+        var w models.DueWord
+        var scheduled, repetitions, lapses sql.NullInt64
+        var ease, interval, stability, difficulty sql.NullFloat64
+        var dueAt, lastReviewed sql.NullTime
+        err := rows.Scan(&w.ID, &w.Japanese, &w.Romaji, &w.English,
+            &scheduled, &ease, &interval, &stability, &difficulty,
+            &repetitions, &lapses, &dueAt, &lastReviewed)
+        if err != nil {
+            return nil, err
+        }
+
+        if scheduled.Valid {
+            w.Schedule = &models.WordSchedule{
+                WordID:       scheduled.Int64,
+                Ease:         ease.Float64,
+                IntervalDays: interval.Float64,
+                Stability:    stability.Float64,
+                Difficulty:   difficulty.Float64,
+                Repetitions:  int(repetitions.Int64),
+                Lapses:       int(lapses.Int64),
+                DueAt:        dueAt.Time,
+            }
+            if lastReviewed.Valid {
+                w.Schedule.LastReviewedAt = &lastReviewed.Time
+            }
+        }
+        words = append(words, w)
+    }
+    return words, rows.Err()
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+    "testing"
+    "context"
+    "time"
+    "github.com/stretchr/testify/assert"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+func TestScheduleRepository_GetDueWords(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    _, err := db.Exec(`
+        INSERT INTO words (japanese, romaji, english)
+        VALUES ('猫', 'neko', 'cat'), ('犬', 'inu', 'dog'), ('鳥', 'tori', 'bird'), ('魚', 'sakana', 'fish')
+    `)
+    assert.NoError(t, err)
+
+    ctx := context.Background()
+    repo := NewScheduleRepository(db)
+    now := time.Date(2025, 2, 8, 12, 0, 0, 0, time.UTC)
+
+    fresh, err := repo.GetSchedule(ctx, 1)
+    assert.NoError(t, err)
+    assert.Equal(t, models.WordSchedule{WordID: 1}, *fresh)
+
+    reviewed := now.AddDate(0, 0, -3)
+    schedules := []models.WordSchedule{
+        {WordID: 1, Ease: 2.5, IntervalDays: 1, Repetitions: 1, DueAt: now.Add(-time.Hour), LastReviewedAt: &reviewed},
+        {WordID: 2, Ease: 2.5, IntervalDays: 6, Repetitions: 2, DueAt: now.AddDate(0, 0, 3), LastReviewedAt: &reviewed},
+        {WordID: 3, Ease: 2.3, IntervalDays: 1, Lapses: 2, DueAt: now.AddDate(0, 0, -2), LastReviewedAt: &reviewed},
+    }
+    for i := range schedules {
+        assert.NoError(t, repo.SaveSchedule(ctx, &schedules[i]))
+    }
+
+    // Saving again updates in place
+    schedules[2].Lapses = 3
+    assert.NoError(t, repo.SaveSchedule(ctx, &schedules[2]))
+
+    saved, err := repo.GetSchedule(ctx, 3)
+    assert.NoError(t, err)
+    assert.Equal(t, 3, saved.Lapses)
+    assert.True(t, saved.DueAt.Equal(now.AddDate(0, 0, -2)))
+
+    due, err := repo.GetDueWords(ctx, now, 10)
+    assert.NoError(t, err)
+    assert.Len(t, due, 3)
+    assert.Equal(t, int64(3), due[0].ID)
+    assert.Equal(t, int64(1), due[1].ID)
+    assert.Equal(t, int64(4), due[2].ID)
+    assert.Nil(t, due[2].Schedule)
+
+    due, err = repo.GetDueWords(ctx, now, 1)
+    assert.NoError(t, err)
+    assert.Len(t, due, 1)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+// WordStore reads and writes the vocabulary. WordRepository implements it.
+type WordStore interface {
+    GetWords(ctx context.Context, filter models.WordFilter, sort models.WordSort, limit, offset int) ([]models.Word, string, error)
+    GetWordsAfter(ctx context.Context, filter models.WordFilter, cursor string, limit int) ([]models.Word, string, error)
+    GetWordsCount(ctx context.Context, filter models.WordFilter) (int64, error)
+    GetWord(ctx context.Context, id int64) (*models.WordDetail, error)
+    CreateWord(ctx context.Context, word *models.Word) (created bool, err error)
+    CreateWords(ctx context.Context, words []models.Word, groupID int64) (updated int, err error)
+    EachWord(ctx context.Context, groupID int64, fn func(models.Word) error) error
+    UpdateWord(ctx context.Context, id int64, word *models.Word) error
+    DeleteWord(ctx context.Context, id int64) error
+    FindDuplicates(ctx context.Context) ([]models.DuplicateSet, error)
+    MergeWords(ctx context.Context, id int64, ids []int64) error
+}
+
+// GroupStore reads and writes groups and their words. GroupRepository
+// implements it.
+type GroupStore interface {
+    GetGroups(ctx context.Context, limit, offset int) ([]models.Group, error)
+    GetGroupsCount(ctx context.Context) (int64, error)
+    GetGroup(ctx context.Context, id int64) (*models.GroupDetail, error)
+    CreateGroup(ctx context.Context, group *models.Group) error
+    UpdateGroup(ctx context.Context, id int64, group *models.Group) error
+    DeleteGroup(ctx context.Context, id int64) error
+    GetGroupWords(ctx context.Context, groupID int64, limit, offset int) ([]models.Word, error)
+    GetGroupWordsCount(ctx context.Context, groupID int64) (int64, error)
+    AddWords(ctx context.Context, groupID int64, wordIDs []int64) error
+    RemoveWord(ctx context.Context, groupID, wordID int64) error
+}
+
+// SessionStore reads and writes study sessions and the answers given in
+// them. StudySessionRepository implements it.
+type SessionStore interface {
+    GetSessions(ctx context.Context, limit, offset int) ([]models.StudySession, error)
+    GetSessionsCount(ctx context.Context) (int64, error)
+    GetActivitySessions(ctx context.Context, activityID int64, limit, offset int) ([]models.StudySession, error)
+    GetActivitySessionsCount(ctx context.Context, activityID int64) (int64, error)
+    GetSession(ctx context.Context, id int64) (*models.StudySession, error)
+    CreateSession(ctx context.Context, req *models.CreateStudySessionRequest) (*models.StudySession, error)
+    CreateReview(ctx context.Context, sessionID, wordID int64, correct bool) (*models.WordReviewItem, error)
+    GetSessionWords(ctx context.Context, sessionID int64, limit, offset int) ([]models.ReviewedWord, error)
+    GetSessionWordsCount(ctx context.Context, sessionID int64) (int64, error)
+}
+
+// ReviewStore keeps the spaced repetition schedule that reviews advance.
+// ScheduleRepository implements it.
+type ReviewStore interface {
+    GetSchedule(ctx context.Context, wordID int64) (*models.WordSchedule, error)
+    SaveSchedule(ctx context.Context, s *models.WordSchedule) error
+    GetDueWords(ctx context.Context, now time.Time, limit int) ([]models.DueWord, error)
+}
+
+var (
+    _ WordStore    = (*WordRepository)(nil)
+    _ GroupStore   = (*GroupRepository)(nil)
+    _ SessionStore = (*StudySessionRepository)(nil)
+    _ ReviewStore  = (*ScheduleRepository)(nil)
+)
+
+// Repos holds one store per aggregate, all reading and writing through the
+// same database or transaction.
+type Repos struct {
+    Words    WordStore
+    Groups   GroupStore
+    Sessions SessionStore
+    Reviews  ReviewStore
+}
+
+// UnitOfWork runs fn with repositories bound to a single transaction. The
+// transaction commits when fn returns nil and rolls back otherwise, so the
+// writes of fn happen all together or not at all.
+type UnitOfWork interface {
+    WithTx(ctx context.Context, fn func(Repos) error) error
+}
+
+// Store hands out the repositories of a database and implements
+// UnitOfWork for it.
+type Store struct {
+    db         *sql.DB
+    duplicates models.DuplicatePolicy
+}
+
+func NewStore(db *sql.DB) *Store {
+    return &Store{db: db, duplicates: models.DuplicateReject}
+}
+
+// SetDuplicatePolicy sets the duplicate policy of the word repositories
+// the store hands out, see WordRepository.SetDuplicatePolicy.
+func (s *Store) SetDuplicatePolicy(policy models.DuplicatePolicy) {
+    s.duplicates = policy
+}
+
+// Repos returns repositories that each run their own transactions.
+func (s *Store) Repos() Repos {
+    return s.repos(s.db)
+}
+
+// WithTx runs fn with repositories sharing one transaction. Repository
+// methods that are transactional on their own run under a savepoint, so fn
+// may handle their errors and carry on.
+func (s *Store) WithTx(ctx context.Context, fn func(Repos) error) error {
+    tx, err := s.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if err := fn(s.repos(tx)); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+func (s *Store) repos(db DBTX) Repos {
+    return Repos{
+        Words:    &WordRepository{db: db, duplicates: s.duplicates},
+        Groups:   &GroupRepository{db: db},
+        Sessions: &StudySessionRepository{db: db},
+        Reviews:  &ScheduleRepository{db: db},
+    }
+}
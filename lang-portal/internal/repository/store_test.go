@@ -0,0 +1,65 @@
+package repository
+
+import (
+    "testing"
+    "context"
+    "errors"
+    "github.com/stretchr/testify/assert"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+func TestStore_WithTx(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    _, err := db.Exec(`
+        INSERT INTO words (japanese, romaji, english) VALUES ('こんにちは', 'konnichiwa', 'hello');
+        INSERT INTO groups (name) VALUES ('Basic Greetings');
+    `)
+    assert.NoError(t, err)
+
+    ctx := context.Background()
+    _, err = BackfillNormKeys(ctx, db)
+    assert.NoError(t, err)
+    store := NewStore(db)
+    count := func(table string) int {
+        var n int
+        assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&n))
+        return n
+    }
+
+    // A failing step rolls back everything written before it
+    err = store.WithTx(ctx, func(repos Repos) error {
+        session, err := repos.Sessions.CreateSession(ctx, &models.CreateStudySessionRequest{GroupID: 1, StudyActivityID: 1})
+        if err != nil {
+            return err
+        }
+        if _, err := repos.Sessions.CreateReview(ctx, session.ID, 1, true); err != nil {
+            return err
+        }
+        _, err = repos.Sessions.CreateReview(ctx, session.ID, 999, true)
+        return err
+    })
+    assert.True(t, errors.Is(err, ErrNotFound))
+    assert.Equal(t, 0, count("study_sessions"))
+    assert.Equal(t, 0, count("word_review_items"))
+
+    // A repository error handled by fn only undoes that call
+    err = store.WithTx(ctx, func(repos Repos) error {
+        _, err := repos.Words.CreateWords(ctx, []models.Word{
+            {Japanese: "ねこ", Romaji: "neko", English: "cat"},
+            {Japanese: "こんにちは", Romaji: "konnichiwa", English: "hello"},
+        }, 0)
+        assert.True(t, errors.Is(err, ErrConflict))
+
+        _, err = repos.Words.CreateWord(ctx, &models.Word{Japanese: "いぬ", Romaji: "inu", English: "dog"})
+        return err
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, 2, count("words"))
+
+    words, _, err := store.Repos().Words.GetWords(ctx, models.WordFilter{}, models.WordSort{}, 10, 0)
+    assert.NoError(t, err)
+    assert.Equal(t, "hello", words[0].English)
+    assert.Equal(t, "dog", words[1].English)
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+    "database/sql"
+    "context"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+type StudyActivityRepository struct {
+    db *sql.DB
+}
+
+func NewStudyActivityRepository(db *sql.DB) *StudyActivityRepository {
+    return &StudyActivityRepository{db: db}
+}
+
+func (r *StudyActivityRepository) GetActivities(ctx context.Context, limit, offset int) ([]models.StudyActivity, error) {
+    ctx, done := observe(ctx, "study_activities", "GetActivities")
+    defer done()
+
+    query := `SELECT id, name, COALESCE(thumbnail_url, ''), COALESCE(description, ''), launch_url, created_at
+              FROM study_activities
+              ORDER BY id
+              LIMIT ? OFFSET ?`
+
+    rows, err := r.db.QueryContext(ctx, query, limit, offset)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    activities := []models.StudyActivity{}
+    for rows.Next() {
+        var a models.StudyActivity
+        if err := rows.Scan(&a.ID, &a.Name, &a.ThumbnailURL, &a.Description, &a.LaunchURL, &a.CreatedAt); err != nil {
+            return nil, err
+        }
+        activities = append(activities, a)
+    }
+    return activities, rows.Err()
+}
+
+func (r *StudyActivityRepository) GetActivitiesCount(ctx context.Context) (int64, error) {
+    ctx, done := observe(ctx, "study_activities", "GetActivitiesCount")
+    defer done()
+
+    var count int64
+    err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM study_activities").Scan(&count)
+    return count, err
+}
+
+func (r *StudyActivityRepository) GetActivity(ctx context.Context, id int64) (*models.StudyActivity, error) {
+    ctx, done := observe(ctx, "study_activities", "GetActivity")
+    defer done()
+
+    query := `SELECT id, name, COALESCE(thumbnail_url, ''), COALESCE(description, ''), launch_url, created_at
+              FROM study_activities
+              WHERE id = ?`
+
+    var a models.StudyActivity
+    err := r.db.QueryRowContext(ctx, query, id).Scan(
+        &a.ID, &a.Name, &a.ThumbnailURL, &a.Description, &a.LaunchURL, &a.CreatedAt,
+    )
+    if err == sql.ErrNoRows {
+        return nil, NotFound("study activity")
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &a, nil
+}
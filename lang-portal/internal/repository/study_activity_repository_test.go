@@ -0,0 +1,50 @@
+package repository
+
+import (
+    "testing"
+    "context"
+    "github.com/stretchr/testify/assert"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+func TestStudyActivityRepository_GetActivities(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    // The migrations seed two activities; add one without optional fields
+    _, err := db.Exec(`
+        INSERT INTO study_activities (name, thumbnail_url, description, launch_url)
+        VALUES ('Kana Writing', NULL, NULL, 'http://localhost:8083');
+        INSERT INTO groups (name) VALUES ('Basic Greetings');
+    `)
+    assert.NoError(t, err)
+
+    ctx := context.Background()
+    repo := NewStudyActivityRepository(db)
+
+    activities, err := repo.GetActivities(ctx, 10, 0)
+    assert.NoError(t, err)
+    assert.Len(t, activities, 3)
+    assert.Equal(t, "Vocabulary Quiz", activities[0].Name)
+    assert.Equal(t, "", activities[2].Description)
+
+    activity, err := repo.GetActivity(ctx, 3)
+    assert.NoError(t, err)
+    assert.Equal(t, "http://localhost:8083", activity.LaunchURL)
+
+    _, err = repo.GetActivity(ctx, 999)
+    assert.EqualError(t, err, "study activity not found")
+
+    sessions := NewStudySessionRepository(db)
+    _, err = sessions.CreateSession(ctx, &models.CreateStudySessionRequest{GroupID: 1, StudyActivityID: 3})
+    assert.NoError(t, err)
+
+    activitySessions, err := sessions.GetActivitySessions(ctx, 3, 10, 0)
+    assert.NoError(t, err)
+    assert.Len(t, activitySessions, 1)
+    assert.Equal(t, "Kana Writing", activitySessions[0].ActivityName)
+
+    count, err := sessions.GetActivitySessionsCount(ctx, 1)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(0), count)
+}
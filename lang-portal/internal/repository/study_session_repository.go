@@ -0,0 +1,295 @@
+package repository
+
+import (
+    "database/sql"
+    "context"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "time"
+)
+
+type StudySessionRepository struct {
+    db DBTX
+}
+
+func NewStudySessionRepository(db *sql.DB) *StudySessionRepository {
+    return &StudySessionRepository{db: db}
+}
+
+// sessionQuery selects sessions with their review totals. The end time is the
+// moment of the last review, or the start time when nothing was reviewed yet.
+const sessionQuery = `
+    SELECT s.id, s.group_id, g.name, s.study_activity_id, COALESCE(a.name, ''), s.created_at,
+           COALESCE(MAX(r.created_at), s.created_at),
+           COUNT(r.id),
+           COALESCE(SUM(CASE WHEN r.correct THEN 1 ELSE 0 END), 0)
+    FROM study_sessions s
+    JOIN groups g ON g.id = s.group_id
+    LEFT JOIN study_activities a ON a.id = s.study_activity_id
+    LEFT JOIN word_review_items r ON r.study_session_id = s.id
+`
+
+func scanSession(row interface{ Scan(...interface{}) error }, s *models.StudySession) error {
+    var endTime string
+    err := row.Scan(&s.ID, &s.GroupID, &s.GroupName, &s.StudyActivityID, &s.ActivityName, &s.StartTime,
+        &endTime, &s.ReviewItemsCount, &s.CorrectCount)
+    if err != nil {
+        return err
+    }
+    s.EndTime = formatTimestamp(endTime)
+    s.WrongCount = s.ReviewItemsCount - s.CorrectCount
+    return nil
+}
+
+func (r *StudySessionRepository) GetSessions(ctx context.Context, limit, offset int) ([]models.StudySession, error) {
+    ctx, done := observe(ctx, "study_sessions", "GetSessions")
+    defer done()
+
+    query := sessionQuery + `
+        GROUP BY s.id
+        ORDER BY s.created_at DESC, s.id DESC
+        LIMIT ? OFFSET ?`
+
+    rows, err := r.db.QueryContext(ctx, query, limit, offset)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    sessions := []models.StudySession{}
+    for rows.Next() {
+        var s models.StudySession
+        if err := scanSession(rows, &s); err != nil {
+            return nil, err
+        }
+        sessions = append(sessions, s)
+    }
+    return sessions, rows.Err()
+}
+
+func (r *StudySessionRepository) GetSessionsCount(ctx context.Context) (int64, error) {
+    ctx, done := observe(ctx, "study_sessions", "GetSessionsCount")
+    defer done()
+
+    var count int64
+    err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM study_sessions").Scan(&count)
+    return count, err
+}
+
+func (r *StudySessionRepository) GetActivitySessions(ctx context.Context, activityID int64, limit, offset int) ([]models.StudySession, error) {
+    ctx, done := observe(ctx, "study_sessions", "GetActivitySessions")
+    defer done()
+
+    query := sessionQuery + `
+        WHERE s.study_activity_id = ?
+        GROUP BY s.id
+        ORDER BY s.created_at DESC, s.id DESC
+        LIMIT ? OFFSET ?`
+
+    rows, err := r.db.QueryContext(ctx, query, activityID, limit, offset)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    sessions := []models.StudySession{}
+    for rows.Next() {
+        var s models.StudySession
+        if err := scanSession(rows, &s); err != nil {
+            return nil, err
+        }
+        sessions = append(sessions, s)
+    }
+    return sessions, rows.Err()
+}
+
+func (r *StudySessionRepository) GetActivitySessionsCount(ctx context.Context, activityID int64) (int64, error) {
+    ctx, done := observe(ctx, "study_sessions", "GetActivitySessionsCount")
+    defer done()
+
+    var count int64
+    err := r.db.QueryRowContext(ctx,
+        "SELECT COUNT(*) FROM study_sessions WHERE study_activity_id = ?", activityID,
+    ).Scan(&count)
+    return count, err
+}
+
+func (r *StudySessionRepository) GetSession(ctx context.Context, id int64) (*models.StudySession, error) {
+    ctx, done := observe(ctx, "study_sessions", "GetSession")
+    defer done()
+
+    query := sessionQuery + `
+        WHERE s.id = ?
+        GROUP BY s.id`
+
+    var s models.StudySession
+    err := scanSession(r.db.QueryRowContext(ctx, query, id), &s)
+    if err == sql.ErrNoRows {
+        return nil, NotFound("study session")
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &s, nil
+}
+
+func (r *StudySessionRepository) CreateSession(ctx context.Context, req *models.CreateStudySessionRequest) (*models.StudySession, error) {
+    ctx, done := observe(ctx, "study_sessions", "CreateSession")
+    defer done()
+
+    tx, err := beginTx(ctx, r.db)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    if err := requireRow(ctx, tx, "SELECT 1 FROM groups WHERE id = ?", req.GroupID, "group"); err != nil {
+        return nil, err
+    }
+    if err := requireRow(ctx, tx, "SELECT 1 FROM study_activities WHERE id = ?", req.StudyActivityID, "study activity"); err != nil {
+        return nil, err
+    }
+
+    result, err := tx.ExecContext(ctx, `
+        INSERT INTO study_sessions (group_id, study_activity_id, created_at)
+        VALUES (?, ?, CURRENT_TIMESTAMP)
+    `, req.GroupID, req.StudyActivityID)
+    if err != nil {
+        return nil, err
+    }
+
+    id, err := result.LastInsertId()
+    if err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+
+    return r.GetSession(ctx, id)
+}
+
+// CreateReview records whether a word was answered correctly in a session.
+func (r *StudySessionRepository) CreateReview(ctx context.Context, sessionID, wordID int64, correct bool) (*models.WordReviewItem, error) {
+    ctx, done := observe(ctx, "study_sessions", "CreateReview")
+    defer done()
+
+    tx, err := beginTx(ctx, r.db)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    if err := requireRow(ctx, tx, "SELECT 1 FROM study_sessions WHERE id = ?", sessionID, "study session"); err != nil {
+        return nil, err
+    }
+    if err := requireRow(ctx, tx, "SELECT 1 FROM words WHERE id = ?", wordID, "word"); err != nil {
+        return nil, err
+    }
+
+    result, err := tx.ExecContext(ctx, `
+        INSERT INTO word_review_items (word_id, study_session_id, correct, created_at)
+        VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+    `, wordID, sessionID, correct)
+    if err != nil {
+        return nil, err
+    }
+
+    id, err := result.LastInsertId()
+    if err != nil {
+        return nil, err
+    }
+
+    item := models.WordReviewItem{
+        ID:             id,
+        WordID:         wordID,
+        StudySessionID: sessionID,
+        Correct:        correct,
+    }
+    err = tx.QueryRowContext(ctx,
+        "SELECT created_at FROM word_review_items WHERE id = ?", id,
+    ).Scan(&item.CreatedAt)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return &item, nil
+}
+
+func (r *StudySessionRepository) GetSessionWords(ctx context.Context, sessionID int64, limit, offset int) ([]models.ReviewedWord, error) {
+    ctx, done := observe(ctx, "study_sessions", "GetSessionWords")
+    defer done()
+
+    query := `SELECT w.id, w.japanese, w.romaji, w.english,
+                     SUM(CASE WHEN r.correct THEN 1 ELSE 0 END),
+                     SUM(CASE WHEN r.correct THEN 0 ELSE 1 END)
+              FROM word_review_items r
+              JOIN words w ON w.id = r.word_id
+              WHERE r.study_session_id = ?
+              GROUP BY w.id
+              ORDER BY w.id
+              LIMIT ? OFFSET ?`
+
+    rows, err := r.db.QueryContext(ctx, query, sessionID, limit, offset)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    words := []models.ReviewedWord{}
+    for rows.Next() {
+        var w models.ReviewedWord
+        if err := rows.Scan(&w.ID, &w.Japanese, &w.Romaji, &w.English, &w.CorrectCount, &w.WrongCount); err != nil {
+            return nil, err
+        }
+        words = append(words, w)
+    }
+    return words, rows.Err()
+}
+
+func (r *StudySessionRepository) GetSessionWordsCount(ctx context.Context, sessionID int64) (int64, error) {
+    ctx, done := observe(ctx, "study_sessions", "GetSessionWordsCount")
+    defer done()
+
+    var count int64
+    err := r.db.QueryRowContext(ctx,
+        "SELECT COUNT(DISTINCT word_id) FROM word_review_items WHERE study_session_id = ?", sessionID,
+    ).Scan(&count)
+    return count, err
+}
+
+// timestampLayouts are the text forms SQLite stores DATETIME values in.
+var timestampLayouts = []string{
+    "2006-01-02 15:04:05.999999999-07:00",
+    "2006-01-02T15:04:05.999999999-07:00",
+    "2006-01-02 15:04:05.999999999",
+    "2006-01-02T15:04:05.999999999",
+    time.RFC3339Nano,
+}
+
+// parseTimestamp reads a DATETIME value returned as text. Values without an
+// offset are UTC, which is what CURRENT_TIMESTAMP writes.
+func parseTimestamp(s string) (time.Time, error) {
+    var err error
+    for _, layout := range timestampLayouts {
+        var t time.Time
+        if t, err = time.Parse(layout, s); err == nil {
+            return t, nil
+        }
+    }
+    return time.Time{}, err
+}
+
+// formatTimestamp normalises timestamps produced by SQL expressions, which
+// the driver hands back as raw text instead of the RFC 3339 form it uses
+// for DATETIME columns.
+func formatTimestamp(s string) string {
+    t, err := parseTimestamp(s)
+    if err != nil {
+        return s
+    }
+    return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+    "testing"
+    "context"
+    "github.com/stretchr/testify/assert"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+func TestStudySessionRepository_Reviews(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    _, err := db.Exec(`
+        INSERT INTO words (japanese, romaji, english)
+        VALUES ('こんにちは', 'konnichiwa', 'hello'), ('さようなら', 'sayounara', 'goodbye');
+        INSERT INTO groups (name) VALUES ('Basic Greetings');
+    `)
+    assert.NoError(t, err)
+
+    ctx := context.Background()
+    repo := NewStudySessionRepository(db)
+
+    session, err := repo.CreateSession(ctx, &models.CreateStudySessionRequest{GroupID: 1, StudyActivityID: 1})
+    assert.NoError(t, err)
+    assert.Equal(t, "Basic Greetings", session.GroupName)
+    assert.Equal(t, "Vocabulary Quiz", session.ActivityName)
+    assert.Equal(t, session.StartTime, session.EndTime)
+
+    for _, review := range []struct {
+        wordID  int64
+        correct bool
+    }{{1, true}, {1, false}, {1, true}, {2, false}} {
+        item, err := repo.CreateReview(ctx, session.ID, review.wordID, review.correct)
+        assert.NoError(t, err)
+        assert.NotEmpty(t, item.CreatedAt)
+    }
+
+    session, err = repo.GetSession(ctx, session.ID)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(4), session.ReviewItemsCount)
+    assert.Equal(t, int64(2), session.CorrectCount)
+    assert.Equal(t, int64(2), session.WrongCount)
+
+    words, err := repo.GetSessionWords(ctx, session.ID, 10, 0)
+    assert.NoError(t, err)
+    assert.Len(t, words, 2)
+    assert.Equal(t, int64(2), words[0].CorrectCount)
+    assert.Equal(t, int64(1), words[0].WrongCount)
+    assert.Equal(t, int64(0), words[1].CorrectCount)
+    assert.Equal(t, int64(1), words[1].WrongCount)
+
+    count, err := repo.GetSessionWordsCount(ctx, session.ID)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(2), count)
+
+    sessions, err := repo.GetSessions(ctx, 10, 0)
+    assert.NoError(t, err)
+    assert.Len(t, sessions, 1)
+}
+
+func TestStudySessionRepository_Errors(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    ctx := context.Background()
+    repo := NewStudySessionRepository(db)
+
+    _, err := repo.CreateSession(ctx, &models.CreateStudySessionRequest{GroupID: 999, StudyActivityID: 1})
+    assert.EqualError(t, err, "group not found")
+
+    _, err = db.Exec("INSERT INTO groups (name) VALUES ('Basic Greetings')")
+    assert.NoError(t, err)
+    _, err = repo.CreateSession(ctx, &models.CreateStudySessionRequest{GroupID: 1, StudyActivityID: 999})
+    assert.EqualError(t, err, "study activity not found")
+
+    _, err = repo.GetSession(ctx, 999)
+    assert.EqualError(t, err, "study session not found")
+
+    _, err = repo.CreateReview(ctx, 999, 1, true)
+    assert.EqualError(t, err, "study session not found")
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+    "bytes"
+    "encoding/base64"
+    "encoding/json"
+    "sort"
+)
+
+// wordSortKeys maps the sortable fields of a word listing to SQL.
+// created_at is compared as text so cursor values round-trip exactly.
+var wordSortKeys = map[string]string{
+    "id":            "w.id",
+    "japanese":      "w.japanese",
+    "romaji":        "w.romaji",
+    "english":       "w.english",
+    "created_at":    "CAST(w.created_at AS TEXT)",
+    "correct_count": "(SELECT COUNT(*) FROM word_review_items r WHERE r.word_id = w.id AND r.correct)",
+}
+
+// WordSortFields lists the fields a word listing can be sorted by.
+func WordSortFields() []string {
+    fields := make([]string, 0, len(wordSortKeys))
+    for field := range wordSortKeys {
+        fields = append(fields, field)
+    }
+    sort.Strings(fields)
+    return fields
+}
+
+// wordCursor is the position after the last word of a page: its sort key
+// and id, which breaks ties between equal keys.
+type wordCursor struct {
+    Sort  string      `json:"s"`
+    Desc  bool        `json:"d,omitempty"`
+    Value interface{} `json:"v"`
+    ID    int64       `json:"id"`
+}
+
+func encodeWordCursor(c wordCursor) (string, error) {
+    body, err := json.Marshal(c)
+    if err != nil {
+        return "", err
+    }
+    return base64.RawURLEncoding.EncodeToString(body), nil
+}
+
+func decodeWordCursor(s string) (*wordCursor, error) {
+    invalid := Invalid("invalid cursor", map[string]string{"cursor": "not a cursor returned by this listing"})
+
+    body, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil {
+        return nil, invalid
+    }
+
+    // Keep numbers exact so integer keys compare equal in SQL
+    var c wordCursor
+    dec := json.NewDecoder(bytes.NewReader(body))
+    dec.UseNumber()
+    if err := dec.Decode(&c); err != nil {
+        return nil, invalid
+    }
+    if _, ok := wordSortKeys[c.Sort]; !ok {
+        return nil, invalid
+    }
+    if n, ok := c.Value.(json.Number); ok {
+        if c.Value, err = n.Int64(); err != nil {
+            return nil, invalid
+        }
+    }
+    return &c, nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "strconv"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/textnorm"
+)
+
+// SetDuplicatePolicy chooses how CreateWord, CreateWords and UpdateWord
+// treat a word whose normalized japanese and english match an existing
+// word. The default is models.DuplicateReject.
+func (r *WordRepository) SetDuplicatePolicy(policy models.DuplicatePolicy) {
+    r.duplicates = policy
+}
+
+// FindDuplicates returns every set of two or more words sharing the same
+// normalized japanese and english, ordered by that text and then by id.
+func (r *WordRepository) FindDuplicates(ctx context.Context) ([]models.DuplicateSet, error) {
+    ctx, done := observe(ctx, "words", "FindDuplicates")
+    defer done()
+
+    rows, err := r.db.QueryContext(ctx, `
+        SELECT w.id, w.japanese, w.romaji, w.english, w.parts, w.created_at, w.updated_at, w.japanese_norm, w.english_norm
+        FROM words w
+        JOIN (SELECT japanese_norm, english_norm
+              FROM words
+              WHERE japanese_norm IS NOT NULL
+              GROUP BY japanese_norm, english_norm
+              HAVING COUNT(*) > 1) d
+          ON d.japanese_norm = w.japanese_norm AND d.english_norm = w.english_norm
+        ORDER BY w.japanese_norm, w.english_norm, w.id
+    `)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    sets := []models.DuplicateSet{}
+    n := 0
+    for rows.Next() {
+        var w models.Word
+        var japanese, english string
+        if err := scanWord(rows, &w, &japanese, &english); err != nil {
+            return nil, err
+        }
+        if n := len(sets); n == 0 || sets[n-1].Japanese != japanese || sets[n-1].English != english {
+            sets = append(sets, models.DuplicateSet{Japanese: japanese, English: english})
+        }
+        sets[len(sets)-1].Words = append(sets[len(sets)-1].Words, w)
+        n++
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    observeRows(ctx, n)
+    return sets, nil
+}
+
+// MergeWords folds the words in ids into the word id: their review history
+// and group memberships move to it, then they are deleted. The word keeps
+// its own review schedule, or takes the first one found among ids if it has
+// none.
+func (r *WordRepository) MergeWords(ctx context.Context, id int64, ids []int64) error {
+    ctx, done := observe(ctx, "words", "MergeWords")
+    defer done()
+
+    tx, err := beginTx(ctx, r.db)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if err := requireRow(ctx, tx, "SELECT 1 FROM words WHERE id = ?", id, "word"); err != nil {
+        return err
+    }
+
+    seen := map[int64]bool{}
+    merged := 0
+    for i, other := range ids {
+        if other == id {
+            return Invalid("cannot merge a word into itself", map[string]string{fmt.Sprintf("word_ids[%d]", i): "must not be the word merged into"})
+        }
+        if seen[other] {
+            continue
+        }
+        seen[other] = true
+        if err := requireRow(ctx, tx, "SELECT 1 FROM words WHERE id = ?", other, "word"); err != nil {
+            return err
+        }
+
+        steps := []string{
+            "UPDATE word_review_items SET word_id = ?1 WHERE word_id = ?2",
+            `INSERT OR IGNORE INTO words_groups (word_id, group_id, created_at)
+             SELECT ?1, group_id, created_at FROM words_groups WHERE word_id = ?2`,
+            `UPDATE word_schedules SET word_id = ?1
+             WHERE word_id = ?2 AND NOT EXISTS (SELECT 1 FROM word_schedules WHERE word_id = ?1)`,
+            // Cascades to the remaining group memberships and schedule
+            "DELETE FROM words WHERE id = ?2",
+        }
+        for _, step := range steps {
+            if _, err := tx.ExecContext(ctx, step, id, other); err != nil {
+                return err
+            }
+        }
+        merged++
+    }
+
+    _, err = tx.ExecContext(ctx, "UPDATE words SET updated_at = CURRENT_TIMESTAMP WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+    observeRows(ctx, merged)
+    return nil
+}
+
+// BackfillNormKeys computes the normalized text of the words that lack it,
+// such as words stored before it existed or written with plain SQL, and
+// returns how many words it filled. It scans the whole table, so it runs
+// only at startup, after migrating, rather than on every write. The
+// repositories and the seed importer keep the keys up to date from then
+// on; SQLite cannot compute them in a trigger, so a word written with
+// plain SQL while the server runs has no keys, and is not found as a
+// duplicate, until the next start.
+func BackfillNormKeys(ctx context.Context, db *sql.DB) (int, error) {
+    tx, err := db.BeginTx(ctx, nil)
+    if err != nil {
+        return 0, err
+    }
+    defer tx.Rollback()
+
+    rows, err := tx.QueryContext(ctx, "SELECT id, japanese, english FROM words WHERE japanese_norm IS NULL OR english_norm IS NULL")
+    if err != nil {
+        return 0, err
+    }
+    defer rows.Close()
+
+    type pending struct {
+        id                int64
+        japanese, english string
+    }
+    var missing []pending
+    for rows.Next() {
+        var p pending
+        if err := rows.Scan(&p.id, &p.japanese, &p.english); err != nil {
+            return 0, err
+        }
+        missing = append(missing, p)
+    }
+    if err := rows.Err(); err != nil {
+        return 0, err
+    }
+    if err := rows.Close(); err != nil {
+        return 0, err
+    }
+    if len(missing) == 0 {
+        return 0, nil
+    }
+
+    update, err := tx.PrepareContext(ctx, "UPDATE words SET japanese_norm = ?, english_norm = ? WHERE id = ?")
+    if err != nil {
+        return 0, err
+    }
+    defer update.Close()
+
+    for _, p := range missing {
+        if _, err := update.ExecContext(ctx, textnorm.Japanese(p.japanese), textnorm.English(p.english), p.id); err != nil {
+            return 0, err
+        }
+    }
+    return len(missing), tx.Commit()
+}
+
+// setNormKeys stores the normalized text of word id. It runs after the text
+// itself is updated, as that update resets the keys.
+func setNormKeys(ctx context.Context, tx DBTX, id int64, japanese, english string) error {
+    _, err := tx.ExecContext(ctx, "UPDATE words SET japanese_norm = ?, english_norm = ? WHERE id = ?", japanese, english, id)
+    return err
+}
+
+// findDuplicate returns the lowest id of a word other than exclude with
+// the given normalized text, or 0 when there is none. Words whose keys are
+// not computed yet never match, see BackfillNormKeys.
+func findDuplicate(ctx context.Context, tx DBTX, japanese, english string, exclude int64) (int64, error) {
+    var id int64
+    err := tx.QueryRowContext(ctx, `
+        SELECT id FROM words
+        WHERE japanese_norm = ? AND english_norm = ? AND id != ?
+        ORDER BY id LIMIT 1
+    `, japanese, english, exclude).Scan(&id)
+    if err == sql.ErrNoRows {
+        return 0, nil
+    }
+    return id, err
+}
+
+// duplicateError reports that a word would duplicate word id.
+func duplicateError(id int64) error {
+    return &Error{
+        Kind:    ErrConflict,
+        Message: fmt.Sprintf("word already exists as word %d", id),
+        Details: map[string]string{"duplicate_id": strconv.FormatInt(id, 10)},
+    }
+}
@@ -3,73 +3,378 @@ package repository
 import (
     "database/sql"
     "context"
+    "encoding/json"
+    "fmt"
     "github.com/karl247ai/lang-portal/internal/models"
-    "errors"
+    "github.com/karl247ai/lang-portal/internal/textnorm"
+    "strings"
 )
 
 type WordRepository struct {
-    db *sql.DB
+    db         DBTX
+    duplicates models.DuplicatePolicy
 }
 
 func NewWordRepository(db *sql.DB) *WordRepository {
-    return &WordRepository{db: db}
+    return &WordRepository{db: db, duplicates: models.DuplicateReject}
 }
 
-func (r *WordRepository) GetWords(ctx context.Context, limit, offset int) ([]models.Word, error) {
-    query := `SELECT id, japanese, romaji, english, parts, created_at, updated_at 
-              FROM words LIMIT ? OFFSET ?`
+// NewWordRepositoryTx returns a repository working inside tx, for callers
+// such as the seed importer that own the transaction. Its transactional
+// methods run under a savepoint, as in Store.WithTx.
+func NewWordRepositoryTx(tx *sql.Tx) *WordRepository {
+    return &WordRepository{db: tx, duplicates: models.DuplicateReject}
+}
+
+// GetWords returns a page of the words matching filter in sort order, and
+// a cursor for GetWordsAfter when more words follow. Relevance ordering has
+// no cursor since ranks shift as words are added.
+func (r *WordRepository) GetWords(ctx context.Context, filter models.WordFilter, sort models.WordSort, limit, offset int) ([]models.Word, string, error) {
+    ctx, done := observe(ctx, "words", "GetWords")
+    defer done()
+
+    words, next, err := r.listWords(ctx, filter, sort, nil, limit, offset)
+    observeRows(ctx, len(words))
+    return words, next, err
+}
+
+// GetWordsAfter returns the words following cursor, in the sort order the
+// cursor was created with. Words inserted meanwhile never shift the pages.
+func (r *WordRepository) GetWordsAfter(ctx context.Context, filter models.WordFilter, cursor string, limit int) ([]models.Word, string, error) {
+    ctx, done := observe(ctx, "words", "GetWordsAfter")
+    defer done()
+
+    after, err := decodeWordCursor(cursor)
+    if err != nil {
+        return nil, "", err
+    }
+    words, next, err := r.listWords(ctx, filter, models.WordSort{Field: after.Sort, Desc: after.Desc}, after, limit, 0)
+    observeRows(ctx, len(words))
+    return words, next, err
+}
+
+func (r *WordRepository) listWords(ctx context.Context, filter models.WordFilter, sort models.WordSort, after *wordCursor, limit, offset int) ([]models.Word, string, error) {
+    from, where, args := wordFilterSQL(filter)
+
+    // With a text query and no explicit sort, the most relevant words come
+    // first, ranked by bm25 with japanese weighted highest
+    key := "NULL"
+    order := "bm25(words_fts, 10.0, 5.0, 5.0, 1.0), w.id"
+    if sort.Field != "" || from == "" {
+        if sort.Field == "" {
+            sort.Field = "id"
+        }
+        var ok bool
+        key, ok = wordSortKeys[sort.Field]
+        if !ok {
+            return nil, "", Invalid("invalid sort", map[string]string{"sort": "must be one of " + strings.Join(WordSortFields(), ", ")})
+        }
+
+        dir, cmp := "ASC", ">"
+        if sort.Desc {
+            dir, cmp = "DESC", "<"
+        }
+        order = key + " " + dir + ", w.id " + dir
+
+        if after != nil {
+            keyset := "(" + key + " " + cmp + " ? OR (" + key + " = ? AND w.id " + cmp + " ?))"
+            if where == "" {
+                where = " WHERE " + keyset
+            } else {
+                where += " AND " + keyset
+            }
+            args = append(args, after.Value, after.Value, after.ID)
+        }
+    }
+
+    // One extra row tells whether there is a next page
+    query := `SELECT w.id, w.japanese, w.romaji, w.english, w.parts, w.created_at, w.updated_at, ` + key + `
+              FROM words w` + from + where + `
+              ORDER BY ` + order + `
+              LIMIT ? OFFSET ?`
               
-    rows, err := r.db.QueryContext(ctx, query, limit, offset)
+    rows, err := r.db.QueryContext(ctx, query, append(args, limit+1, offset)...)
     if (err != nil) {
-        return nil, err
+        return nil, "", err
     }
     defer rows.Close()
 
     var words []models.Word
+    var lastKey interface{}
     for rows.Next() {
+        if len(words) == limit {
+            if key == "NULL" {
+                return words, "", nil
+            }
+            last := words[len(words)-1]
+            next, err := encodeWordCursor(wordCursor{Sort: sort.Field, Desc: sort.Desc, Value: lastKey, ID: last.ID})
+            return words, next, err
+        }
+
         var w models.Word
-        err := rows.Scan(&w.ID, &w.Japanese, &w.Romaji, &w.English, &w.Parts, &w.CreatedAt, &w.UpdatedAt)
+        err := scanWord(rows, &w, &lastKey)
         if err != nil {
-            return nil, err
+            return nil, "", err
         }
         words = append(words, w)
     }
-    return words, nil
+    return words, "", rows.Err()
 }
 
-func (r *WordRepository) CreateWord(ctx context.Context, word *models.Word) error {
-    query := `
-        INSERT INTO words (japanese, romaji, english, parts, created_at, updated_at)
-        VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-    `
-    
-    result, err := r.db.ExecContext(ctx, query, 
-        word.Japanese, 
-        word.Romaji, 
-        word.English, 
-        word.Parts,
-    )
+// GetWord returns a word with its review totals and the groups it belongs
+// to, ordered by name.
+func (r *WordRepository) GetWord(ctx context.Context, id int64) (*models.WordDetail, error) {
+    ctx, done := observe(ctx, "words", "GetWord")
+    defer done()
+
+    query := `SELECT w.id, w.japanese, w.romaji, w.english, w.parts, w.created_at, w.updated_at,
+                     (SELECT COUNT(*) FROM word_review_items r WHERE r.word_id = w.id AND r.correct),
+                     (SELECT COUNT(*) FROM word_review_items r WHERE r.word_id = w.id AND NOT r.correct),
+                     (SELECT json_group_array(json_object('id', g.id, 'name', g.name))
+                      FROM (SELECT g.id, g.name
+                            FROM groups g
+                            JOIN words_groups wg ON wg.group_id = g.id
+                            WHERE wg.word_id = w.id
+                            ORDER BY g.name, g.id) g)
+              FROM words w
+              WHERE w.id = ?`
+
+    rows, err := r.db.QueryContext(ctx, query, id)
     if err != nil {
-        return err
+        return nil, err
     }
+    defer rows.Close()
+
+    if !rows.Next() {
+        if err := rows.Err(); err != nil {
+            return nil, err
+        }
+        return nil, NotFound("word")
+    }
+
+    var d models.WordDetail
+    var groups string
+    if err := scanWord(rows, &d.Word, &d.Stats.CorrectCount, &d.Stats.WrongCount, &groups); err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal([]byte(groups), &d.Groups); err != nil {
+        return nil, err
+    }
+    observeRows(ctx, 1)
+    return &d, nil
+}
+
+// CreateWord saves a new word, applying the duplicate policy: a duplicate
+// is a conflict error under DuplicateReject, and under DuplicateUpsert the
+// existing word is updated instead and created is false. word.ID is set to
+// the id of the word saved.
+func (r *WordRepository) CreateWord(ctx context.Context, word *models.Word) (created bool, err error) {
+    ctx, done := observe(ctx, "words", "CreateWord")
+    defer done()
+
+    tx, err := beginTx(ctx, r.db)
+    if err != nil {
+        return false, err
+    }
+    defer tx.Rollback()
+
+    duplicate, err := r.saveWord(ctx, tx, word)
+    if err != nil {
+        return false, err
+    }
+    if duplicate != 0 && r.duplicates == models.DuplicateReject {
+        return false, duplicateError(duplicate)
+    }
+    if err := tx.Commit(); err != nil {
+        return false, err
+    }
+    observeRows(ctx, 1)
+    return duplicate == 0, nil
+}
 
-    id, err := result.LastInsertId()
+// saveWord inserts word, or updates its duplicate under DuplicateUpsert,
+// setting word.ID. Under DuplicateReject nothing is written and the id of
+// the duplicate is returned; in the other cases the duplicate id is that of
+// the word updated, or 0 when the word was inserted.
+func (r *WordRepository) saveWord(ctx context.Context, tx DBTX, word *models.Word) (duplicate int64, err error) {
+    japanese, english := textnorm.Japanese(word.Japanese), textnorm.English(word.English)
+    if r.duplicates != models.DuplicateAllow {
+        if duplicate, err = findDuplicate(ctx, tx, japanese, english, 0); err != nil {
+            return 0, err
+        }
+    }
+
+    switch {
+    case duplicate != 0 && r.duplicates == models.DuplicateReject:
+        return duplicate, nil
+    case duplicate != 0:
+        word.ID = duplicate
+        _, err := tx.ExecContext(ctx, `
+            UPDATE words
+            SET japanese = ?, romaji = ?, english = ?, parts = ?, updated_at = CURRENT_TIMESTAMP
+            WHERE id = ?
+        `, word.Japanese, word.Romaji, word.English, word.Parts, duplicate)
+        if err != nil {
+            return 0, err
+        }
+        return duplicate, setNormKeys(ctx, tx, duplicate, japanese, english)
+    }
+
+    result, err := tx.ExecContext(ctx, `
+        INSERT INTO words (japanese, romaji, english, parts, japanese_norm, english_norm, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `, word.Japanese, word.Romaji, word.English, word.Parts, japanese, english)
+    if err != nil {
+        return 0, err
+    }
+    word.ID, err = result.LastInsertId()
+    return 0, err
+}
+
+// CreateWords saves words in a single transaction, adding each to groupID
+// unless it is zero. Either every word is saved or none is. Duplicates are
+// handled as in CreateWord, counting words that repeat an earlier one of the
+// batch; under DuplicateReject the conflict error lists every duplicate,
+// keyed words[N]. updated counts the words that updated an existing word.
+func (r *WordRepository) CreateWords(ctx context.Context, words []models.Word, groupID int64) (updated int, err error) {
+    ctx, done := observe(ctx, "words", "CreateWords")
+    defer done()
+
+    tx, err := beginTx(ctx, r.db)
+    if err != nil {
+        return 0, err
+    }
+    defer tx.Rollback()
+
+    if groupID != 0 {
+        if err := requireRow(ctx, tx, "SELECT 1 FROM groups WHERE id = ?", groupID, "group"); err != nil {
+            return 0, err
+        }
+    }
+
+    // Under DuplicateReject nothing is written, so repeats within the batch
+    // are tracked here
+    batch := map[[2]string]int{}
+    conflicts := map[string]string{}
+    for i := range words {
+        if r.duplicates == models.DuplicateReject {
+            key := [2]string{textnorm.Japanese(words[i].Japanese), textnorm.English(words[i].English)}
+            if first, ok := batch[key]; ok {
+                conflicts[fmt.Sprintf("words[%d]", i)] = fmt.Sprintf("repeats words[%d]", first)
+                continue
+            }
+            batch[key] = i
+        }
+
+        duplicate, err := r.saveWord(ctx, tx, &words[i])
+        if err != nil {
+            return 0, err
+        }
+        if duplicate != 0 {
+            if r.duplicates == models.DuplicateReject {
+                conflicts[fmt.Sprintf("words[%d]", i)] = fmt.Sprintf("already exists as word %d", duplicate)
+                continue
+            }
+            updated++
+        }
+
+        if groupID != 0 {
+            _, err := tx.ExecContext(ctx, `
+                INSERT OR IGNORE INTO words_groups (word_id, group_id, created_at)
+                VALUES (?, ?, CURRENT_TIMESTAMP)
+            `, words[i].ID, groupID)
+            if err != nil {
+                return 0, err
+            }
+        }
+    }
+
+    if len(conflicts) > 0 {
+        return 0, &Error{
+            Kind:    ErrConflict,
+            Message: fmt.Sprintf("%d of %d words already exist, nothing was saved", len(conflicts), len(words)),
+            Details: conflicts,
+        }
+    }
+    if err := tx.Commit(); err != nil {
+        return 0, err
+    }
+    observeRows(ctx, len(words))
+    return updated, nil
+}
+
+// EachWord calls fn for every word in id order, or only for the words of
+// groupID unless it is zero, without loading the vocabulary into memory.
+func (r *WordRepository) EachWord(ctx context.Context, groupID int64, fn func(models.Word) error) error {
+    ctx, done := observe(ctx, "words", "EachWord")
+    defer done()
+
+    if groupID != 0 {
+        var one int
+        err := r.db.QueryRowContext(ctx, "SELECT 1 FROM groups WHERE id = ?", groupID).Scan(&one)
+        if err == sql.ErrNoRows {
+            return NotFound("group")
+        }
+        if err != nil {
+            return err
+        }
+    }
+
+    from, where, args := wordFilterSQL(models.WordFilter{GroupID: groupID})
+    rows, err := r.db.QueryContext(ctx, `SELECT w.id, w.japanese, w.romaji, w.english, w.parts, w.created_at, w.updated_at
+                                         FROM words w`+from+where+`
+                                         ORDER BY w.id`, args...)
     if err != nil {
         return err
     }
+    defer rows.Close()
 
-    word.ID = id
-    return nil
+    n := 0
+    defer func() { observeRows(ctx, n) }()
+    for rows.Next() {
+        var w models.Word
+        if err := scanWord(rows, &w); err != nil {
+            return err
+        }
+        n++
+        if err := fn(w); err != nil {
+            return err
+        }
+    }
+    return rows.Err()
 }
 
+// UpdateWord replaces the fields of word id. Unless the duplicate policy is
+// DuplicateAllow, changing a word into a duplicate of another is a conflict
+// error; an update has no other word to upsert into.
 func (r *WordRepository) UpdateWord(ctx context.Context, id int64, word *models.Word) error {
+    ctx, done := observe(ctx, "words", "UpdateWord")
+    defer done()
+
+    tx, err := beginTx(ctx, r.db)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    japanese, english := textnorm.Japanese(word.Japanese), textnorm.English(word.English)
+    if r.duplicates != models.DuplicateAllow {
+        duplicate, err := findDuplicate(ctx, tx, japanese, english, id)
+        if err != nil {
+            return err
+        }
+        if duplicate != 0 {
+            return duplicateError(duplicate)
+        }
+    }
+
     query := `
         UPDATE words 
         SET japanese = ?, romaji = ?, english = ?, parts = ?, updated_at = CURRENT_TIMESTAMP
         WHERE id = ?
     `
     
-    result, err := r.db.ExecContext(ctx, query, 
+    result, err := tx.ExecContext(ctx, query, 
         word.Japanese, 
         word.Romaji, 
         word.English, 
@@ -86,13 +391,23 @@ func (r *WordRepository) UpdateWord(ctx context.Context, id int64, word *models.
     }
 
     if rowsAffected == 0 {
-        return errors.New("word not found")
+        return NotFound("word")
     }
 
+    if err := setNormKeys(ctx, tx, id, japanese, english); err != nil {
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+    observeRows(ctx, int(rowsAffected))
     return nil
 }
 
 func (r *WordRepository) DeleteWord(ctx context.Context, id int64) error {
+    ctx, done := observe(ctx, "words", "DeleteWord")
+    defer done()
+
     result, err := r.db.ExecContext(ctx, "DELETE FROM words WHERE id = ?", id)
     if err != nil {
         return err
@@ -104,14 +419,84 @@ func (r *WordRepository) DeleteWord(ctx context.Context, id int64) error {
     }
 
     if rowsAffected == 0 {
-        return errors.New("word not found")
+        return NotFound("word")
     }
 
+    observeRows(ctx, int(rowsAffected))
     return nil
 }
 
-func (r *WordRepository) GetWordsCount(ctx context.Context) (int64, error) {
+func (r *WordRepository) GetWordsCount(ctx context.Context, filter models.WordFilter) (int64, error) {
+    ctx, done := observe(ctx, "words", "GetWordsCount")
+    defer done()
+
+    from, where, args := wordFilterSQL(filter)
+
     var count int64
-    err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM words").Scan(&count)
+    err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM words w"+from+where, args...).Scan(&count)
     return count, err
-}
\ No newline at end of file
+}
+
+// wordFilterSQL returns the join and WHERE clause selecting the words that
+// match filter. from is empty unless a full-text query is involved.
+func wordFilterSQL(filter models.WordFilter) (from, where string, args []interface{}) {
+    var conds []string
+    if match := ftsQuery(filter.Query, filter.Prefix); match != "" {
+        from = " JOIN words_fts ON words_fts.rowid = w.id"
+        conds = append(conds, "words_fts MATCH ?")
+        args = append(args, match)
+    }
+    if filter.GroupID != 0 {
+        conds = append(conds, "w.id IN (SELECT word_id FROM words_groups WHERE group_id = ?)")
+        args = append(args, filter.GroupID)
+    }
+    if filter.PartOfSpeech != "" {
+        conds = append(conds, "json_extract(w.parts, '$.part_of_speech') = ?")
+        args = append(args, strings.ToLower(filter.PartOfSpeech))
+    }
+    if filter.JLPTLevel != 0 {
+        conds = append(conds, "json_extract(w.parts, '$.jlpt_level') = ?")
+        args = append(args, int(filter.JLPTLevel))
+    }
+    if filter.Kanji != "" {
+        conds = append(conds, "EXISTS (SELECT 1 FROM json_each(w.parts, '$.components') c WHERE json_extract(c.value, '$.kanji') = ?)")
+        args = append(args, filter.Kanji)
+    }
+    if filter.HasExamples != nil {
+        cond := "json_array_length(w.parts, '$.examples') > 0"
+        if !*filter.HasExamples {
+            cond = "COALESCE(json_array_length(w.parts, '$.examples'), 0) = 0"
+        }
+        conds = append(conds, cond)
+    }
+    if filter.PitchAccent != nil {
+        conds = append(conds, "json_extract(w.parts, '$.pitch_accent') = ?")
+        args = append(args, *filter.PitchAccent)
+    }
+
+    if len(conds) > 0 {
+        where = " WHERE " + strings.Join(conds, " AND ")
+    }
+    return from, where, args
+}
+
+// ftsQuery turns free text into an FTS5 query in which every term must
+// match. Terms are quoted so user input cannot use FTS5 query syntax.
+func ftsQuery(text string, prefix bool) string {
+    var terms []string
+    for _, term := range strings.Fields(text) {
+        term = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+        if prefix {
+            term += "*"
+        }
+        terms = append(terms, term)
+    }
+    return strings.Join(terms, " ")
+}
+
+// scanWord reads a row of the standard word columns followed by any extra
+// columns. A NULL parts column leaves Parts nil.
+func scanWord(rows *sql.Rows, w *models.Word, extra ...interface{}) error {
+    dest := append([]interface{}{&w.ID, &w.Japanese, &w.Romaji, &w.English, &w.Parts, &w.CreatedAt, &w.UpdatedAt}, extra...)
+    return rows.Scan(dest...)
+}
@@ -5,27 +5,12 @@ import (
     "context"
     "database/sql"
     "github.com/stretchr/testify/assert"
-    _ "github.com/mattn/go-sqlite3"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/testdb"
 )
 
 func setupTestDB(t *testing.T) *sql.DB {
-    db, err := sql.Open("sqlite3", ":memory:")
-    assert.NoError(t, err)
-
-    // Create test table
-    _, err = db.Exec(`
-        CREATE TABLE words (
-            id INTEGER PRIMARY KEY AUTOINCREMENT,
-            japanese TEXT NOT NULL,
-            romaji TEXT NOT NULL,
-            english TEXT NOT NULL,
-            parts JSON,
-            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-        )
-    `)
-    assert.NoError(t, err)
-    return db
+    return testdb.Open(t)
 }
 
 func TestWordRepository_GetWords(t *testing.T) {
@@ -40,11 +25,385 @@ func TestWordRepository_GetWords(t *testing.T) {
     assert.NoError(t, err)
 
     repo := NewWordRepository(db)
-    words, err := repo.GetWords(context.Background(), 10, 0)
+    words, next, err := repo.GetWords(context.Background(), models.WordFilter{}, models.WordSort{}, 10, 0)
     
     assert.NoError(t, err)
+    assert.Empty(t, next)
     assert.Len(t, words, 1)
     assert.Equal(t, "こんにちは", words[0].Japanese)
     assert.Equal(t, "konnichiwa", words[0].Romaji)
     assert.Equal(t, "hello", words[0].English)
-}
\ No newline at end of file
+}
+
+func TestWordRepository_SearchWords(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    _, err := db.Exec(`
+        INSERT INTO words (id, japanese, romaji, english, parts) VALUES
+            (1, '食べる', 'taberu', 'to eat', '{"part_of_speech": "verb", "components": [{"kanji": "食", "romaji": ["ta"]}]}'),
+            (2, '食べ物', 'tabemono', 'food', '{"part_of_speech": "noun"}'),
+            (3, '飲む', 'nomu', 'to drink', NULL);
+        INSERT INTO groups (id, name) VALUES (1, 'Verbs');
+        INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (3, 1);
+    `)
+    assert.NoError(t, err)
+
+    repo := NewWordRepository(db)
+    ctx := context.Background()
+
+    search := func(filter models.WordFilter) []int64 {
+        words, _, err := repo.GetWords(ctx, filter, models.WordSort{}, 10, 0)
+        assert.NoError(t, err)
+        count, err := repo.GetWordsCount(ctx, filter)
+        assert.NoError(t, err)
+        assert.Equal(t, int64(len(words)), count)
+
+        ids := []int64{}
+        for _, w := range words {
+            ids = append(ids, w.ID)
+        }
+        return ids
+    }
+
+    assert.ElementsMatch(t, []int64{1, 3}, search(models.WordFilter{Query: "to"}))
+    assert.ElementsMatch(t, []int64{3}, search(models.WordFilter{Query: "TO Drink"}))
+    assert.ElementsMatch(t, []int64{}, search(models.WordFilter{Query: "tabe"}))
+    assert.ElementsMatch(t, []int64{1, 2}, search(models.WordFilter{Query: "tabe", Prefix: true}))
+    assert.ElementsMatch(t, []int64{1, 2}, search(models.WordFilter{Query: "食べ", Prefix: true}))
+    // Text inside parts is searchable, its keys are not
+    assert.ElementsMatch(t, []int64{1}, search(models.WordFilter{Query: "食"}))
+    assert.ElementsMatch(t, []int64{}, search(models.WordFilter{Query: "kanji"}))
+    // FTS5 syntax in user input is treated as plain text
+    assert.ElementsMatch(t, []int64{}, search(models.WordFilter{Query: `"eat OR NOT(`}))
+
+    assert.ElementsMatch(t, []int64{1, 3}, search(models.WordFilter{GroupID: 1}))
+    assert.ElementsMatch(t, []int64{3}, search(models.WordFilter{GroupID: 1, Query: "drink"}))
+    assert.ElementsMatch(t, []int64{2}, search(models.WordFilter{PartOfSpeech: "Noun"}))
+
+    // Writes keep the index in sync
+    _, err = db.Exec("UPDATE words SET english = 'to consume' WHERE id = 1")
+    assert.NoError(t, err)
+    assert.ElementsMatch(t, []int64{1}, search(models.WordFilter{Query: "consume"}))
+    assert.ElementsMatch(t, []int64{}, search(models.WordFilter{Query: "eat"}))
+
+    _, err = db.Exec("DELETE FROM words WHERE id = 3")
+    assert.NoError(t, err)
+    assert.ElementsMatch(t, []int64{}, search(models.WordFilter{Query: "drink"}))
+}
+
+func TestWordRepository_SearchRanking(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    // "neko" only in parts for word 1, in romaji for word 2
+    _, err := db.Exec(`
+        INSERT INTO words (id, japanese, romaji, english, parts) VALUES
+            (1, '子猫', 'koneko', 'kitten', '{"related": "neko"}'),
+            (2, '猫', 'neko', 'cat', NULL);
+    `)
+    assert.NoError(t, err)
+
+    words, next, err := NewWordRepository(db).GetWords(context.Background(), models.WordFilter{Query: "neko"}, models.WordSort{}, 1, 0)
+    assert.NoError(t, err)
+    assert.Len(t, words, 1)
+    assert.Equal(t, int64(2), words[0].ID)
+    // Relevance order has no stable keyset
+    assert.Empty(t, next)
+}
+
+func TestWordRepository_SortAndCursor(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    _, err := db.Exec(`
+        INSERT INTO words (id, japanese, romaji, english) VALUES
+            (1, '猫', 'neko', 'cat'),
+            (2, '犬', 'inu', 'dog'),
+            (3, '鳥', 'tori', 'bird'),
+            (4, '魚', 'sakana', 'fish');
+        INSERT INTO groups (id, name) VALUES (1, 'Animals');
+        INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 1, 1);
+        INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES
+            (2, 1, 1), (2, 1, 1), (4, 1, 1), (4, 1, 1), (3, 1, 1), (1, 1, 0);
+    `)
+    assert.NoError(t, err)
+
+    repo := NewWordRepository(db)
+    ctx := context.Background()
+    ids := func(words []models.Word) []int64 {
+        ids := []int64{}
+        for _, w := range words {
+            ids = append(ids, w.ID)
+        }
+        return ids
+    }
+
+    words, next, err := repo.GetWords(ctx, models.WordFilter{}, models.WordSort{Field: "english"}, 2, 0)
+    assert.NoError(t, err)
+    assert.Equal(t, []int64{3, 1}, ids(words))
+    assert.NotEmpty(t, next)
+
+    // A word sorting before the cursor does not shift the next page
+    _, err = db.Exec("INSERT INTO words (id, japanese, romaji, english) VALUES (5, '蟻', 'ari', 'ant')")
+    assert.NoError(t, err)
+
+    words, next, err = repo.GetWordsAfter(ctx, models.WordFilter{}, next, 2)
+    assert.NoError(t, err)
+    assert.Equal(t, []int64{2, 4}, ids(words))
+    assert.Empty(t, next)
+
+    // Ties on correct_count are broken by id in the same direction
+    var all []int64
+    words, next, err = repo.GetWords(ctx, models.WordFilter{}, models.WordSort{Field: "correct_count", Desc: true}, 2, 0)
+    assert.NoError(t, err)
+    all = append(all, ids(words)...)
+    for next != "" {
+        words, next, err = repo.GetWordsAfter(ctx, models.WordFilter{}, next, 2)
+        assert.NoError(t, err)
+        all = append(all, ids(words)...)
+    }
+    assert.Equal(t, []int64{4, 2, 3, 5, 1}, all)
+
+    _, _, err = repo.GetWords(ctx, models.WordFilter{}, models.WordSort{Field: "parts"}, 2, 0)
+    assert.ErrorIs(t, err, ErrValidation)
+    _, _, err = repo.GetWordsAfter(ctx, models.WordFilter{}, "not-a-cursor", 2)
+    assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestWordRepository_GetWord(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    _, err := db.Exec(`
+        INSERT INTO words (id, japanese, romaji, english) VALUES (1, 'こんにちは', 'konnichiwa', 'hello'), (2, '猫', 'neko', 'cat');
+        INSERT INTO groups (id, name) VALUES (1, 'Greetings'), (2, 'Basics');
+        INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (1, 2);
+        INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 1, 1);
+        INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (1, 1, 1), (1, 1, 1), (1, 1, 0);
+    `)
+    assert.NoError(t, err)
+
+    repo := NewWordRepository(db)
+    ctx := context.Background()
+
+    word, err := repo.GetWord(ctx, 1)
+    assert.NoError(t, err)
+    assert.Equal(t, "konnichiwa", word.Romaji)
+    assert.Equal(t, models.WordStats{CorrectCount: 2, WrongCount: 1}, word.Stats)
+    assert.Equal(t, []models.WordGroup{{ID: 2, Name: "Basics"}, {ID: 1, Name: "Greetings"}}, word.Groups)
+
+    word, err = repo.GetWord(ctx, 2)
+    assert.NoError(t, err)
+    assert.Equal(t, models.WordStats{}, word.Stats)
+    assert.Empty(t, word.Groups)
+    assert.NotNil(t, word.Groups)
+
+    _, err = repo.GetWord(ctx, 999)
+    assert.ErrorIs(t, err, ErrNotFound)
+    assert.EqualError(t, err, "word not found")
+}
+
+
+func TestWordRepository_CreateWordsAndEachWord(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+
+    res, err := db.Exec("INSERT INTO groups (name) VALUES ('Animals')")
+    assert.NoError(t, err)
+    groupID, _ := res.LastInsertId()
+
+    repo := NewWordRepository(db)
+    words := []models.Word{
+        {Japanese: "猫", Romaji: "neko", English: "cat"},
+        {Japanese: "犬", Romaji: "inu", English: "dog", Parts: &models.Parts{PartOfSpeech: "noun"}},
+    }
+    _, err = repo.CreateWords(ctx, words, groupID)
+    assert.NoError(t, err)
+    assert.NotZero(t, words[0].ID)
+    assert.NotZero(t, words[1].ID)
+
+    // A missing group rolls the whole batch back
+    _, err = repo.CreateWords(ctx, []models.Word{{Japanese: "鳥", Romaji: "tori", English: "bird"}}, 999)
+    assert.ErrorIs(t, err, ErrNotFound)
+
+    _, err = repo.CreateWords(ctx, []models.Word{{Japanese: "水", Romaji: "mizu", English: "water"}}, 0)
+    assert.NoError(t, err)
+
+    var all []string
+    err = repo.EachWord(ctx, 0, func(w models.Word) error {
+        all = append(all, w.Romaji)
+        return nil
+    })
+    assert.NoError(t, err)
+    assert.Equal(t, []string{"neko", "inu", "mizu"}, all)
+
+    var grouped []models.Word
+    err = repo.EachWord(ctx, groupID, func(w models.Word) error {
+        grouped = append(grouped, w)
+        return nil
+    })
+    assert.NoError(t, err)
+    assert.Len(t, grouped, 2)
+    assert.Equal(t, &models.Parts{PartOfSpeech: "noun"}, grouped[1].Parts)
+
+    err = repo.EachWord(ctx, 999, func(models.Word) error { return nil })
+    assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestWordRepository_PartsFilters(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    _, err := db.Exec(`
+        INSERT INTO words (id, japanese, romaji, english, parts) VALUES
+            (1, '猫', 'neko', 'cat', '{"part_of_speech": "noun", "jlpt_level": 5, "components": [{"kanji": "猫"}], "pitch_accent": 1, "examples": [{"japanese": "猫です。", "english": "It is a cat."}]}'),
+            (2, '子猫', 'koneko', 'kitten', '{"part_of_speech": "noun", "jlpt_level": 3, "components": [{"kanji": "子"}, {"kanji": "猫"}], "pitch_accent": 0, "examples": []}'),
+            (3, '食べる', 'taberu', 'to eat', '{"part_of_speech": "verb", "jlpt_level": 5}'),
+            (4, '水', 'mizu', 'water', NULL);
+    `)
+    assert.NoError(t, err)
+
+    repo := NewWordRepository(db)
+    ctx := context.Background()
+    search := func(filter models.WordFilter) []int64 {
+        words, _, err := repo.GetWords(ctx, filter, models.WordSort{}, 10, 0)
+        assert.NoError(t, err)
+        count, err := repo.GetWordsCount(ctx, filter)
+        assert.NoError(t, err)
+        assert.Equal(t, int64(len(words)), count)
+
+        ids := []int64{}
+        for _, w := range words {
+            ids = append(ids, w.ID)
+        }
+        return ids
+    }
+    yes, no := true, false
+    flat, first := 0, 1
+
+    assert.ElementsMatch(t, []int64{1, 3}, search(models.WordFilter{JLPTLevel: 5}))
+    assert.ElementsMatch(t, []int64{1}, search(models.WordFilter{JLPTLevel: 5, PartOfSpeech: "noun"}))
+    assert.ElementsMatch(t, []int64{1, 2}, search(models.WordFilter{Kanji: "猫"}))
+    assert.ElementsMatch(t, []int64{2}, search(models.WordFilter{Kanji: "子"}))
+    assert.ElementsMatch(t, []int64{1}, search(models.WordFilter{HasExamples: &yes}))
+    assert.ElementsMatch(t, []int64{2, 3, 4}, search(models.WordFilter{HasExamples: &no}))
+    assert.ElementsMatch(t, []int64{2}, search(models.WordFilter{PitchAccent: &flat}))
+    assert.ElementsMatch(t, []int64{1}, search(models.WordFilter{PitchAccent: &first}))
+}
+
+func TestWordRepository_DuplicatePolicy(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+    ctx := context.Background()
+
+    // Written without the repository, so without normalized text yet
+    _, err := db.Exec(`
+        INSERT INTO words (id, japanese, romaji, english) VALUES (1, 'ネコ', 'neko', 'Cat');
+        INSERT INTO groups (id, name) VALUES (1, 'Animals');
+    `)
+    assert.NoError(t, err)
+    // Filled in as at startup
+    n, err := BackfillNormKeys(ctx, db)
+    assert.NoError(t, err)
+    assert.Equal(t, 1, n)
+
+    repo := NewWordRepository(db)
+    word := models.Word{Japanese: "ﾈｺ", Romaji: "neko", English: " cat "}
+    created, err := repo.CreateWord(ctx, &word)
+    assert.False(t, created)
+    assert.ErrorIs(t, err, ErrConflict)
+    assert.Equal(t, map[string]string{"duplicate_id": "1"}, err.(*Error).Details)
+
+    // Only the pair counts as a duplicate
+    word = models.Word{Japanese: "ねこ", Romaji: "neko", English: "kitty"}
+    created, err = repo.CreateWord(ctx, &word)
+    assert.NoError(t, err)
+    assert.True(t, created)
+    assert.Equal(t, int64(2), word.ID)
+
+    err = repo.UpdateWord(ctx, 2, &models.Word{Japanese: "ねこ", Romaji: "neko", English: "CAT"})
+    assert.ErrorIs(t, err, ErrConflict)
+
+    _, err = repo.CreateWords(ctx, []models.Word{
+        {Japanese: "犬", Romaji: "inu", English: "dog"},
+        {Japanese: "ねこ", Romaji: "neko", English: "cat"},
+        {Japanese: "犬", Romaji: "inu", English: "Dog"},
+    }, 0)
+    assert.ErrorIs(t, err, ErrConflict)
+    assert.Equal(t, map[string]string{"words[1]": "already exists as word 1", "words[2]": "repeats words[0]"}, err.(*Error).Details)
+
+    repo.SetDuplicatePolicy(models.DuplicateUpsert)
+    word = models.Word{Japanese: "ねこ", Romaji: "neko", English: "cat", Parts: &models.Parts{PartOfSpeech: "noun"}}
+    created, err = repo.CreateWord(ctx, &word)
+    assert.NoError(t, err)
+    assert.False(t, created)
+    assert.Equal(t, int64(1), word.ID)
+    stored, err := repo.GetWord(ctx, 1)
+    assert.NoError(t, err)
+    assert.Equal(t, "ねこ", stored.Japanese)
+    assert.Equal(t, "noun", stored.Parts.PartOfSpeech)
+
+    words := []models.Word{{Japanese: "猫", Romaji: "neko", English: "cat"}, {Japanese: "ネコ", Romaji: "neko", English: "cat"}}
+    updated, err := repo.CreateWords(ctx, words, 1)
+    assert.NoError(t, err)
+    assert.Equal(t, 1, updated)
+    assert.Equal(t, int64(1), words[1].ID)
+
+    repo.SetDuplicatePolicy(models.DuplicateAllow)
+    word = models.Word{Japanese: "猫", Romaji: "neko", English: "cat"}
+    created, err = repo.CreateWord(ctx, &word)
+    assert.NoError(t, err)
+    assert.True(t, created)
+    assert.NoError(t, repo.UpdateWord(ctx, 2, &models.Word{Japanese: "ネコ", Romaji: "neko", English: "cat"}))
+
+    sets, err := repo.FindDuplicates(ctx)
+    assert.NoError(t, err)
+    assert.Len(t, sets, 2)
+    assert.Equal(t, "ねこ", sets[0].Japanese)
+    assert.Equal(t, "cat", sets[0].English)
+    ids := []int64{}
+    for _, w := range sets[0].Words {
+        ids = append(ids, w.ID)
+    }
+    assert.Equal(t, []int64{1, 2}, ids)
+    assert.Equal(t, "猫", sets[1].Japanese)
+    assert.Len(t, sets[1].Words, 2)
+}
+
+func TestWordRepository_MergeWords(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    _, err := db.Exec(`
+        INSERT INTO words (id, japanese, romaji, english) VALUES
+            (1, '猫', 'neko', 'cat'), (2, 'ネコ', 'neko', 'cat'), (3, 'ねこ', 'neko', 'cat');
+        INSERT INTO groups (id, name) VALUES (1, 'Animals'), (2, 'Basics');
+        INSERT INTO words_groups (word_id, group_id) VALUES (1, 1), (2, 1), (3, 2);
+        INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 1, 1);
+        INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (1, 1, 1), (2, 1, 0), (3, 1, 1);
+        INSERT INTO word_schedules (word_id, due_at) VALUES (3, '2030-01-01 00:00:00');
+    `)
+    assert.NoError(t, err)
+
+    repo := NewWordRepository(db)
+    ctx := context.Background()
+
+    assert.ErrorIs(t, repo.MergeWords(ctx, 1, []int64{2, 1}), ErrValidation)
+    assert.ErrorIs(t, repo.MergeWords(ctx, 1, []int64{2, 99}), ErrNotFound)
+    assert.ErrorIs(t, repo.MergeWords(ctx, 99, []int64{2}), ErrNotFound)
+
+    assert.NoError(t, repo.MergeWords(ctx, 1, []int64{2, 3, 2}))
+
+    word, err := repo.GetWord(ctx, 1)
+    assert.NoError(t, err)
+    assert.Equal(t, models.WordStats{CorrectCount: 2, WrongCount: 1}, word.Stats)
+    assert.Equal(t, []models.WordGroup{{ID: 1, Name: "Animals"}, {ID: 2, Name: "Basics"}}, word.Groups)
+
+    var schedules, remaining int
+    assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM word_schedules WHERE word_id = 1").Scan(&schedules))
+    assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM words").Scan(&remaining))
+    assert.Equal(t, 1, schedules)
+    assert.Equal(t, 1, remaining)
+}
@@ -0,0 +1,50 @@
+package seed
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "strings"
+)
+
+// Mapping declares that the words in File belong to Group.
+type Mapping struct {
+    File  string
+    Group string
+    Line  int
+}
+
+// ParseManifest reads the seed manifest DSL. Each non-empty line maps a
+// seed file to the group it populates:
+//
+//     # comments start with a hash
+//     core_verbs.json => Core Verbs
+//
+// A file may be listed more than once to add its words to several groups.
+func ParseManifest(r io.Reader) ([]Mapping, error) {
+    var mappings []Mapping
+    scanner := bufio.NewScanner(r)
+    line := 0
+    for scanner.Scan() {
+        line++
+        text := strings.TrimSpace(scanner.Text())
+        if text == "" || strings.HasPrefix(text, "#") {
+            continue
+        }
+
+        parts := strings.SplitN(text, "=>", 2)
+        if len(parts) != 2 {
+            return nil, fmt.Errorf("manifest line %d: expected \"<file> => <group>\"", line)
+        }
+        file := strings.TrimSpace(parts[0])
+        group := strings.TrimSpace(parts[1])
+        if file == "" || group == "" {
+            return nil, fmt.Errorf("manifest line %d: file and group must not be empty", line)
+        }
+        mappings = append(mappings, Mapping{File: file, Group: group, Line: line})
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, err
+    }
+    return mappings, nil
+}
@@ -0,0 +1,229 @@
+// Package seed imports the JSON word lists in the seeds folder into groups
+// as declared by the seed manifest.
+package seed
+
+import (
+    "bytes"
+    "context"
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io/fs"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/validator"
+)
+
+// DefaultManifest is the manifest file name looked up in the seeds folder.
+const DefaultManifest = "groups.seed"
+
+// Entry is one word in a seed file.
+type Entry struct {
+    Kanji   string          `json:"kanji"`
+    Romaji  string          `json:"romaji"`
+    English string          `json:"english"`
+    Parts   *models.Parts   `json:"parts,omitempty"`
+}
+
+// EntryError describes a seed entry that failed validation or clashes with
+// an existing word under the duplicate policy.
+type EntryError struct {
+    File  string
+    Index int
+    Err   error
+}
+
+func (e EntryError) Error() string {
+    return fmt.Sprintf("%s[%d]: %v", e.File, e.Index, e.Err)
+}
+
+// Result counts what an import did. Skipped covers both entries that were
+// already up to date and invalid or duplicate entries, which are also
+// listed in Invalid.
+type Result struct {
+    Inserted int
+    Updated  int
+    Skipped  int
+    Invalid  []EntryError
+}
+
+// Importer writes seed files into the database.
+type Importer struct {
+    db         *sql.DB
+    fsys       fs.FS
+    duplicates models.DuplicatePolicy
+}
+
+func NewImporter(db *sql.DB, fsys fs.FS) *Importer {
+    return &Importer{db: db, fsys: fsys, duplicates: models.DuplicateReject}
+}
+
+// SetDuplicatePolicy sets the policy the words are written under, see
+// WordRepository.SetDuplicatePolicy. Entries rejected as duplicates are
+// skipped and listed in Result.Invalid.
+func (i *Importer) SetDuplicatePolicy(policy models.DuplicatePolicy) {
+    i.duplicates = policy
+}
+
+// Run imports every mapping of the manifest in a single transaction. Words
+// are matched on japanese and romaji, so running the same seeds again only
+// updates entries whose english or parts changed.
+func (i *Importer) Run(ctx context.Context, manifest string) (*Result, error) {
+    tx, err := i.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    result, err := i.RunTx(ctx, tx, manifest)
+    if err != nil {
+        return nil, err
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return result, nil
+}
+
+// RunTx imports the manifest like Run, inside the caller's transaction.
+func (i *Importer) RunTx(ctx context.Context, tx *sql.Tx, manifest string) (*Result, error) {
+    f, err := i.fsys.Open(manifest)
+    if err != nil {
+        return nil, err
+    }
+    mappings, err := ParseManifest(f)
+    f.Close()
+    if err != nil {
+        return nil, err
+    }
+
+    words := repository.NewWordRepositoryTx(tx)
+    words.SetDuplicatePolicy(i.duplicates)
+
+    result := &Result{}
+    for _, m := range mappings {
+        entries, err := i.readFile(m.File)
+        if err != nil {
+            return nil, fmt.Errorf("manifest line %d: %w", m.Line, err)
+        }
+
+        groupID, err := ensureGroup(ctx, tx, m.Group)
+        if err != nil {
+            return nil, err
+        }
+
+        for idx, entry := range entries {
+            word := models.Word{
+                Japanese: entry.Kanji,
+                Romaji:   entry.Romaji,
+                English:  entry.English,
+                Parts:    entry.Parts,
+            }
+            if err := validator.ValidateWord(&word); err != nil {
+                result.Skipped++
+                result.Invalid = append(result.Invalid, EntryError{File: m.File, Index: idx, Err: err})
+                continue
+            }
+
+            wordID, err := upsertWord(ctx, tx, words, &word, result)
+            if errors.Is(err, repository.ErrConflict) {
+                result.Skipped++
+                result.Invalid = append(result.Invalid, EntryError{File: m.File, Index: idx, Err: err})
+                continue
+            }
+            if err != nil {
+                return nil, fmt.Errorf("%s[%d]: %w", m.File, idx, err)
+            }
+
+            _, err = tx.ExecContext(ctx,
+                "INSERT OR IGNORE INTO words_groups (word_id, group_id) VALUES (?, ?)",
+                wordID, groupID,
+            )
+            if err != nil {
+                return nil, err
+            }
+        }
+    }
+
+    return result, nil
+}
+
+func (i *Importer) readFile(name string) ([]Entry, error) {
+    body, err := fs.ReadFile(i.fsys, name)
+    if err != nil {
+        return nil, err
+    }
+
+    var entries []Entry
+    if err := json.Unmarshal(body, &entries); err != nil {
+        return nil, fmt.Errorf("parse %s: %w", name, err)
+    }
+    return entries, nil
+}
+
+func ensureGroup(ctx context.Context, tx *sql.Tx, name string) (int64, error) {
+    if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO groups (name) VALUES (?)", name); err != nil {
+        return 0, err
+    }
+
+    var id int64
+    err := tx.QueryRowContext(ctx, "SELECT id FROM groups WHERE name = ?", name).Scan(&id)
+    return id, err
+}
+
+// upsertWord inserts the word or brings an existing match up to date
+// through words, which applies the duplicate policy, and records the
+// outcome in result. A clash with another word under the policy is a
+// conflict error and nothing is written.
+func upsertWord(ctx context.Context, tx *sql.Tx, words *repository.WordRepository, word *models.Word, result *Result) (int64, error) {
+    var id int64
+    var current string
+    var parts []byte
+    err := tx.QueryRowContext(ctx,
+        "SELECT id, english, parts FROM words WHERE japanese = ? AND romaji = ? ORDER BY id LIMIT 1",
+        word.Japanese, word.Romaji,
+    ).Scan(&id, &current, &parts)
+
+    if err == sql.ErrNoRows {
+        created, err := words.CreateWord(ctx, word)
+        if err != nil {
+            return 0, err
+        }
+        if created {
+            result.Inserted++
+        } else {
+            result.Updated++
+        }
+        return word.ID, nil
+    }
+    if err != nil {
+        return 0, err
+    }
+
+    if current == word.English && sameParts(parts, word.Parts) {
+        result.Skipped++
+        return id, nil
+    }
+
+    if err := words.UpdateWord(ctx, id, word); err != nil {
+        return 0, err
+    }
+    result.Updated++
+    return id, nil
+}
+
+// sameParts compares stored parts JSON with the parts of a seed entry.
+func sameParts(stored []byte, parts *models.Parts) bool {
+    if len(stored) == 0 || parts == nil {
+        return len(stored) == 0 && parts == nil
+    }
+
+    var current models.Parts
+    if err := current.Scan(stored); err != nil {
+        return false
+    }
+    a, errA := json.Marshal(current)
+    b, errB := json.Marshal(parts)
+    return errA == nil && errB == nil && bytes.Equal(a, b)
+}
@@ -0,0 +1,170 @@
+package seed
+
+import (
+    "context"
+    "database/sql"
+    "strconv"
+    "strings"
+    "testing"
+    "testing/fstest"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/testdb"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+    return testdb.Open(t)
+}
+
+func testFS() fstest.MapFS {
+    return fstest.MapFS{
+        "groups.seed": {Data: []byte(`
+# verbs go into two groups
+verbs.json => Core Verbs
+verbs.json => Favourites
+`)},
+        "verbs.json": {Data: []byte(`[
+            {"kanji": "払う", "romaji": "harau", "english": "to pay", "parts": [{"kanji": "払", "romaji": ["ha", "ra"]}]},
+            {"kanji": "行く", "romaji": "iku", "english": "to go"},
+            {"kanji": "", "romaji": "nani", "english": "what"}
+        ]`)},
+    }
+}
+
+func count(t *testing.T, db *sql.DB, query string, args ...interface{}) int {
+    var n int
+    require.NoError(t, db.QueryRow(query, args...).Scan(&n))
+    return n
+}
+
+func TestParseManifest(t *testing.T) {
+    mappings, err := ParseManifest(strings.NewReader("# comment\n\n a.json =>  Group A \nb.json=>B"))
+    require.NoError(t, err)
+    assert.Equal(t, []Mapping{
+        {File: "a.json", Group: "Group A", Line: 3},
+        {File: "b.json", Group: "B", Line: 4},
+    }, mappings)
+
+    _, err = ParseManifest(strings.NewReader("a.json Group A"))
+    assert.EqualError(t, err, `manifest line 1: expected "<file> => <group>"`)
+
+    _, err = ParseManifest(strings.NewReader("a.json =>"))
+    assert.EqualError(t, err, "manifest line 1: file and group must not be empty")
+}
+
+func TestImporter_Run(t *testing.T) {
+    db := setupTestDB(t)
+    ctx := context.Background()
+    fsys := testFS()
+
+    result, err := NewImporter(db, fsys).Run(ctx, DefaultManifest)
+    require.NoError(t, err)
+
+    // The second mapping sees the words the first one inserted
+    assert.Equal(t, 2, result.Inserted)
+    assert.Equal(t, 0, result.Updated)
+    assert.Equal(t, 4, result.Skipped)
+    require.Len(t, result.Invalid, 2)
+    assert.Equal(t, "verbs.json[2]: japanese is required", result.Invalid[0].Error())
+
+    assert.Equal(t, 2, count(t, db, "SELECT COUNT(*) FROM words"))
+    assert.Equal(t, 2, count(t, db, "SELECT COUNT(*) FROM groups"))
+    assert.Equal(t, 4, count(t, db, "SELECT COUNT(*) FROM words_groups"))
+}
+
+func TestImporter_RunIsIdempotent(t *testing.T) {
+    db := setupTestDB(t)
+    ctx := context.Background()
+    fsys := testFS()
+
+    _, err := NewImporter(db, fsys).Run(ctx, DefaultManifest)
+    require.NoError(t, err)
+
+    result, err := NewImporter(db, fsys).Run(ctx, DefaultManifest)
+    require.NoError(t, err)
+    assert.Equal(t, 0, result.Inserted)
+    assert.Equal(t, 0, result.Updated)
+    assert.Equal(t, 6, result.Skipped)
+
+    assert.Equal(t, 2, count(t, db, "SELECT COUNT(*) FROM words"))
+    assert.Equal(t, 4, count(t, db, "SELECT COUNT(*) FROM words_groups"))
+}
+
+func TestImporter_RunUpdatesChangedWords(t *testing.T) {
+    db := setupTestDB(t)
+    ctx := context.Background()
+    fsys := testFS()
+
+    _, err := NewImporter(db, fsys).Run(ctx, DefaultManifest)
+    require.NoError(t, err)
+
+    fsys["groups.seed"] = &fstest.MapFile{Data: []byte("verbs.json => Core Verbs")}
+    fsys["verbs.json"] = &fstest.MapFile{Data: []byte(`[
+        {"kanji": "払う", "romaji": "harau", "english": "to pay", "parts": [ {"kanji": "払", "romaji": ["ha", "ra"]} ]},
+        {"kanji": "行く", "romaji": "iku", "english": "to go (somewhere)"}
+    ]`)}
+
+    result, err := NewImporter(db, fsys).Run(ctx, DefaultManifest)
+    require.NoError(t, err)
+    assert.Equal(t, 0, result.Inserted)
+    assert.Equal(t, 1, result.Updated)
+    assert.Equal(t, 1, result.Skipped)
+    assert.Equal(t, 1, count(t, db, "SELECT COUNT(*) FROM words WHERE english = ?", "to go (somewhere)"))
+}
+
+func TestImporter_RunDuplicatePolicy(t *testing.T) {
+    db := setupTestDB(t)
+    ctx := context.Background()
+    existing := models.Word{Japanese: "ネコ", Romaji: "neko", English: "Cat"}
+    _, err := repository.NewWordRepository(db).CreateWord(ctx, &existing)
+    require.NoError(t, err)
+
+    fsys := fstest.MapFS{
+        "groups.seed": {Data: []byte("animals.json => Animals")},
+        "animals.json": {Data: []byte(`[
+            {"kanji": "ねこ", "romaji": "neko", "english": "cat"},
+            {"kanji": "犬", "romaji": "inu", "english": "dog"}
+        ]`)},
+    }
+
+    // The seed's ねこ only differs from ネコ by script and case
+    result, err := NewImporter(db, fsys).Run(ctx, DefaultManifest)
+    require.NoError(t, err)
+    assert.Equal(t, 1, result.Inserted)
+    assert.Equal(t, 1, result.Skipped)
+    require.Len(t, result.Invalid, 1)
+    var conflict *repository.Error
+    require.ErrorAs(t, result.Invalid[0].Err, &conflict)
+    assert.Equal(t, repository.ErrConflict, conflict.Kind)
+    assert.Equal(t, strconv.FormatInt(existing.ID, 10), conflict.Details["duplicate_id"])
+    assert.Equal(t, 0, result.Invalid[0].Index)
+    assert.Equal(t, 2, count(t, db, "SELECT COUNT(*) FROM words"))
+    assert.Equal(t, 0, count(t, db, "SELECT COUNT(*) FROM words WHERE japanese_norm IS NULL OR english_norm IS NULL"))
+
+    importer := NewImporter(db, fsys)
+    importer.SetDuplicatePolicy(models.DuplicateUpsert)
+    result, err = importer.Run(ctx, DefaultManifest)
+    require.NoError(t, err)
+    assert.Equal(t, 1, result.Updated)
+    assert.Empty(t, result.Invalid)
+    assert.Equal(t, 1, count(t, db, "SELECT COUNT(*) FROM words WHERE id = ? AND japanese = 'ねこ' AND english = 'cat'", existing.ID))
+    assert.Equal(t, 2, count(t, db, "SELECT COUNT(*) FROM words_groups"))
+    assert.Equal(t, 0, count(t, db, "SELECT COUNT(*) FROM words WHERE japanese_norm IS NULL OR english_norm IS NULL"))
+
+    importer.SetDuplicatePolicy(models.DuplicateAllow)
+    fsys["animals.json"] = &fstest.MapFile{Data: []byte(`[{"kanji": "ネコ", "romaji": "neko", "english": "CAT"}]`)}
+    result, err = importer.Run(ctx, DefaultManifest)
+    require.NoError(t, err)
+    assert.Equal(t, 1, result.Inserted)
+}
+
+func TestImporter_RunMissingFile(t *testing.T) {
+    db := setupTestDB(t)
+    fsys := fstest.MapFS{"groups.seed": {Data: []byte("missing.json => Nothing")}}
+
+    _, err := NewImporter(db, fsys).Run(context.Background(), DefaultManifest)
+    assert.Error(t, err)
+    assert.Equal(t, 0, count(t, db, "SELECT COUNT(*) FROM groups"))
+}
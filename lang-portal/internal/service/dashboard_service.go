@@ -0,0 +1,87 @@
+package service
+
+import (
+    "context"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+)
+
+// DashboardService computes the figures shown on the frontend dashboard.
+// Calendar-day based stats are evaluated in loc unless a caller overrides it.
+type DashboardService struct {
+    repo *repository.DashboardRepository
+    loc  *time.Location
+    now  func() time.Time
+}
+
+func NewDashboardService(repo *repository.DashboardRepository, loc *time.Location) *DashboardService {
+    if loc == nil {
+        loc = time.UTC
+    }
+    return &DashboardService{repo: repo, loc: loc, now: time.Now}
+}
+
+func (s *DashboardService) LastStudySession(ctx context.Context) (*models.StudySession, error) {
+    return s.repo.GetLastStudySession(ctx)
+}
+
+func (s *DashboardService) StudyProgress(ctx context.Context) (*models.StudyProgress, error) {
+    return s.repo.GetStudyProgress(ctx)
+}
+
+// QuickStats returns the overview stats, counting the study streak in loc.
+// A nil loc falls back to the service's configured time zone.
+func (s *DashboardService) QuickStats(ctx context.Context, loc *time.Location) (*models.QuickStats, error) {
+    stats, err := s.repo.GetQuickStats(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    if loc == nil {
+        loc = s.loc
+    }
+    stats.StudyStreakDays, err = s.studyStreak(ctx, s.now().In(loc))
+    if err != nil {
+        return nil, err
+    }
+    return stats, nil
+}
+
+// studyStreak counts consecutive calendar days with at least one review,
+// ending today or, if nothing was reviewed yet today, yesterday. It jumps
+// from day to day with one indexed lookup each instead of scanning reviews.
+func (s *DashboardService) studyStreak(ctx context.Context, now time.Time) (int, error) {
+    today := startOfDay(now)
+    cursor := today.AddDate(0, 0, 1)
+    expected := today
+    streak := 0
+
+    for {
+        latest, found, err := s.repo.GetLatestReviewBefore(ctx, cursor)
+        if err != nil {
+            return 0, err
+        }
+        if !found {
+            break
+        }
+
+        day := startOfDay(latest.In(now.Location()))
+        if streak == 0 && day.Equal(today.AddDate(0, 0, -1)) {
+            expected = day
+        }
+        if !day.Equal(expected) {
+            break
+        }
+
+        streak++
+        expected = day.AddDate(0, 0, -1)
+        cursor = day
+    }
+    return streak, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+    y, m, d := t.Date()
+    return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+    "testing"
+    "context"
+    "database/sql"
+    "time"
+    "github.com/stretchr/testify/assert"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/testdb"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+    return testdb.Open(t)
+}
+
+func insertReview(t *testing.T, db *sql.DB, correct bool, at time.Time) {
+    _, err := db.Exec(
+        "INSERT INTO word_review_items (word_id, study_session_id, correct, created_at) VALUES (1, 1, ?, ?)",
+        correct, at.UTC().Format("2006-01-02 15:04:05"),
+    )
+    assert.NoError(t, err)
+}
+
+func TestDashboardService_QuickStats(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    tokyo, err := time.LoadLocation("Asia/Tokyo")
+    assert.NoError(t, err)
+
+    // 2025-02-10 09:00 in Tokyo is still 2025-02-10 00:00 UTC
+    now := time.Date(2025, 2, 10, 9, 0, 0, 0, tokyo)
+
+    _, err = db.Exec("INSERT INTO study_sessions (group_id, study_activity_id) VALUES (1, 1), (2, 1), (2, 1)")
+    assert.NoError(t, err)
+
+    insertReview(t, db, true, now.Add(-1*time.Hour))                      // Feb 10, 08:00 Tokyo
+    insertReview(t, db, true, time.Date(2025, 2, 9, 23, 30, 0, 0, tokyo)) // Feb 9
+    insertReview(t, db, false, time.Date(2025, 2, 8, 1, 0, 0, 0, tokyo))  // Feb 8
+    insertReview(t, db, true, time.Date(2025, 2, 6, 12, 0, 0, 0, tokyo))  // gap on Feb 7
+
+    svc := NewDashboardService(repository.NewDashboardRepository(db), tokyo)
+    svc.now = func() time.Time { return now }
+
+    stats, err := svc.QuickStats(context.Background(), nil)
+    assert.NoError(t, err)
+    assert.Equal(t, 75.0, stats.SuccessRate)
+    assert.Equal(t, int64(3), stats.TotalStudySessions)
+    assert.Equal(t, int64(2), stats.TotalActiveGroups)
+    assert.Equal(t, 3, stats.StudyStreakDays)
+
+    // In UTC "today" is Feb 10 without a review yet, the first two reviews
+    // fall on Feb 9 and the Feb 8 Tokyo review lands on Feb 7.
+    stats, err = svc.QuickStats(context.Background(), time.UTC)
+    assert.NoError(t, err)
+    assert.Equal(t, 1, stats.StudyStreakDays)
+}
+
+func TestDashboardService_StreakBroken(t *testing.T) {
+    db := setupTestDB(t)
+    defer db.Close()
+
+    now := time.Date(2025, 2, 10, 12, 0, 0, 0, time.UTC)
+    insertReview(t, db, true, now.AddDate(0, 0, -2))
+
+    svc := NewDashboardService(repository.NewDashboardRepository(db), time.UTC)
+    svc.now = func() time.Time { return now }
+
+    stats, err := svc.QuickStats(context.Background(), nil)
+    assert.NoError(t, err)
+    assert.Equal(t, 0, stats.StudyStreakDays)
+}
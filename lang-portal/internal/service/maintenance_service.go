@@ -0,0 +1,154 @@
+package service
+
+import (
+    "context"
+    "crypto/rand"
+    "database/sql"
+    "encoding/hex"
+    "sync"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/backup"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/seed"
+)
+
+// ConfirmationTTL is how long a reset confirmation token stays valid.
+const ConfirmationTTL = 5 * time.Minute
+
+// Actions that must be confirmed with a token.
+const (
+    ActionResetHistory = "reset_history"
+    ActionFullReset    = "full_reset"
+)
+
+// ConfirmationRequired is returned when a reset is requested without a
+// valid token. Token is a new single-use token for Action; repeating the
+// request with it before ExpiresAt performs the reset.
+type ConfirmationRequired struct {
+    Action    string
+    Token     string
+    ExpiresAt time.Time
+    // Rejected reports that a token was given but was unknown, expired,
+    // already used or issued for another action.
+    Rejected bool
+}
+
+func (e *ConfirmationRequired) Error() string {
+    if e.Rejected {
+        return "confirmation token is invalid or expired, repeat the request with the new confirm_token"
+    }
+    return "repeat the request with the confirm_token to confirm " + e.Action
+}
+
+type pendingConfirmation struct {
+    action    string
+    expiresAt time.Time
+}
+
+// MaintenanceService performs the destructive resets of the settings page.
+// Each reset needs a confirmation token and first snapshots the database,
+// so a reset confirmed by mistake can be undone by restoring the backup.
+type MaintenanceService struct {
+    repo    *repository.MaintenanceRepository
+    backups *backup.Manager
+    seeder  *seed.Importer
+    now     func() time.Time
+
+    mu     sync.Mutex
+    tokens map[string]pendingConfirmation
+}
+
+func NewMaintenanceService(repo *repository.MaintenanceRepository, backups *backup.Manager, seeder *seed.Importer) *MaintenanceService {
+    return &MaintenanceService{
+        repo:    repo,
+        backups: backups,
+        seeder:  seeder,
+        now:     time.Now,
+        tokens:  map[string]pendingConfirmation{},
+    }
+}
+
+// ResetHistory deletes all study sessions, reviews and review schedules,
+// keeping the vocabulary.
+func (s *MaintenanceService) ResetHistory(ctx context.Context, token string) (*models.ResetResult, error) {
+    if err := s.confirm(ActionResetHistory, token); err != nil {
+        return nil, err
+    }
+
+    snapshot, err := s.backups.Snapshot(ctx, "pre-reset-history")
+    if err != nil {
+        return nil, err
+    }
+    deleted, err := s.repo.ResetHistory(ctx)
+    if err != nil {
+        return nil, err
+    }
+    return &models.ResetResult{Message: "Study history has been reset", Backup: *snapshot, Deleted: deleted}, nil
+}
+
+// FullReset deletes all study history, words and groups, then imports the
+// seed files again.
+func (s *MaintenanceService) FullReset(ctx context.Context, token string) (*models.ResetResult, error) {
+    if err := s.confirm(ActionFullReset, token); err != nil {
+        return nil, err
+    }
+
+    snapshot, err := s.backups.Snapshot(ctx, "pre-full-reset")
+    if err != nil {
+        return nil, err
+    }
+    var seeded seed.Result
+    deleted, err := s.repo.FullReset(ctx, func(ctx context.Context, tx *sql.Tx) error {
+        result, err := s.seeder.RunTx(ctx, tx, seed.DefaultManifest)
+        if err != nil {
+            return err
+        }
+        seeded = *result
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &models.ResetResult{
+        Message: "System has been fully reset",
+        Backup:  *snapshot,
+        Deleted: deleted,
+        Seeded:  seeded.Inserted,
+    }, nil
+}
+
+// confirm consumes token if it was issued for action and has not expired.
+// Otherwise it issues a new token and returns it in a
+// *ConfirmationRequired error.
+func (s *MaintenanceService) confirm(action, token string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    now := s.now()
+    for t, p := range s.tokens {
+        if !now.Before(p.expiresAt) {
+            delete(s.tokens, t)
+        }
+    }
+
+    if p, ok := s.tokens[token]; ok {
+        delete(s.tokens, token)
+        if p.action == action {
+            return nil
+        }
+    }
+
+    buf := make([]byte, 16)
+    if _, err := rand.Read(buf); err != nil {
+        return err
+    }
+    issued := &ConfirmationRequired{
+        Action:    action,
+        Token:     hex.EncodeToString(buf),
+        ExpiresAt: now.Add(ConfirmationTTL),
+        Rejected:  token != "",
+    }
+    s.tokens[issued.Token] = pendingConfirmation{action: action, expiresAt: issued.ExpiresAt}
+    return issued
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+    "context"
+    "database/sql"
+    "os"
+    "path/filepath"
+    "testing"
+    "testing/fstest"
+    "time"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/karl247ai/lang-portal/internal/backup"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/seed"
+)
+
+func setupMaintenance(t *testing.T, seeds fstest.MapFS) (*MaintenanceService, *sql.DB, string) {
+    db := setupTestDB(t)
+    t.Cleanup(func() { db.Close() })
+
+    _, err := db.Exec(`
+        INSERT INTO words (id, japanese, romaji, english) VALUES (1, '猫', 'neko', 'cat');
+        INSERT INTO groups (id, name) VALUES (1, 'Animals');
+        INSERT INTO words_groups (word_id, group_id) VALUES (1, 1);
+        INSERT INTO study_sessions (id, group_id, study_activity_id) VALUES (1, 1, 1);
+        INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (1, 1, 1), (1, 1, 0);
+        INSERT INTO word_schedules (word_id, due_at) VALUES (1, '2030-01-01 00:00:00');
+    `)
+    require.NoError(t, err)
+
+    dir := t.TempDir()
+    s := NewMaintenanceService(repository.NewMaintenanceRepository(db), backup.NewManager(db, dir, 0), seed.NewImporter(db, seeds))
+    return s, db, dir
+}
+
+func rows(t *testing.T, db *sql.DB, table string) int {
+    var n int
+    require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&n))
+    return n
+}
+
+func TestMaintenanceService_ResetHistory(t *testing.T) {
+    s, db, dir := setupMaintenance(t, nil)
+    ctx := context.Background()
+
+    // Nothing happens without a token
+    _, err := s.ResetHistory(ctx, "")
+    var confirm *ConfirmationRequired
+    require.ErrorAs(t, err, &confirm)
+    assert.Equal(t, ActionResetHistory, confirm.Action)
+    assert.False(t, confirm.Rejected)
+    assert.Equal(t, 2, rows(t, db, "word_review_items"))
+
+    // A token only confirms the action it was issued for
+    _, err = s.FullReset(ctx, confirm.Token)
+    var rejected *ConfirmationRequired
+    require.ErrorAs(t, err, &rejected)
+    assert.True(t, rejected.Rejected)
+    assert.Equal(t, 1, rows(t, db, "words"))
+
+    _, err = s.ResetHistory(ctx, "")
+    require.ErrorAs(t, err, &confirm)
+    result, err := s.ResetHistory(ctx, confirm.Token)
+    require.NoError(t, err)
+    assert.Equal(t, map[string]int64{"word_review_items": 2, "word_schedules": 1, "study_sessions": 1}, result.Deleted)
+    assert.Equal(t, 0, rows(t, db, "word_review_items"))
+    assert.Equal(t, 1, rows(t, db, "words_groups"))
+
+    // The snapshot still holds the history
+    backupDB, err := sql.Open("sqlite3", filepath.Join(dir, result.Backup.Name))
+    require.NoError(t, err)
+    defer backupDB.Close()
+    assert.Equal(t, 2, rows(t, backupDB, "word_review_items"))
+
+    // Tokens are single-use
+    _, err = s.ResetHistory(ctx, confirm.Token)
+    require.ErrorAs(t, err, &rejected)
+    assert.True(t, rejected.Rejected)
+}
+
+func TestMaintenanceService_TokenExpiry(t *testing.T) {
+    s, _, _ := setupMaintenance(t, nil)
+    now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+    s.now = func() time.Time { return now }
+
+    _, err := s.ResetHistory(context.Background(), "")
+    var confirm *ConfirmationRequired
+    require.ErrorAs(t, err, &confirm)
+    assert.Equal(t, now.Add(ConfirmationTTL), confirm.ExpiresAt)
+
+    now = now.Add(ConfirmationTTL)
+    _, err = s.ResetHistory(context.Background(), confirm.Token)
+    require.ErrorAs(t, err, &confirm)
+    assert.True(t, confirm.Rejected)
+}
+
+func TestMaintenanceService_FullReset(t *testing.T) {
+    seeds := fstest.MapFS{
+        "groups.seed": {Data: []byte("verbs.json => Core Verbs\n")},
+        "verbs.json":  {Data: []byte(`[{"kanji": "行く", "romaji": "iku", "english": "to go"}]`)},
+    }
+    s, db, dir := setupMaintenance(t, seeds)
+    ctx := context.Background()
+
+    _, err := s.FullReset(ctx, "")
+    var confirm *ConfirmationRequired
+    require.ErrorAs(t, err, &confirm)
+    result, err := s.FullReset(ctx, confirm.Token)
+    require.NoError(t, err)
+    assert.Equal(t, 1, result.Seeded)
+    assert.Equal(t, int64(1), result.Deleted["words"])
+
+    var id int64
+    var english string
+    require.NoError(t, db.QueryRow("SELECT id, english FROM words").Scan(&id, &english))
+    // Ids start over
+    assert.Equal(t, int64(1), id)
+    assert.Equal(t, "to go", english)
+    assert.Equal(t, 0, rows(t, db, "study_sessions"))
+    assert.NotZero(t, rows(t, db, "study_activities"))
+
+    entries, err := os.ReadDir(dir)
+    require.NoError(t, err)
+    assert.Len(t, entries, 1)
+}
+
+func TestMaintenanceService_FullResetSeedFailure(t *testing.T) {
+    s, db, _ := setupMaintenance(t, fstest.MapFS{})
+    ctx := context.Background()
+
+    _, err := s.FullReset(ctx, "")
+    var confirm *ConfirmationRequired
+    require.ErrorAs(t, err, &confirm)
+    _, err = s.FullReset(ctx, confirm.Token)
+    assert.Error(t, err)
+    assert.Equal(t, 1, rows(t, db, "words"))
+    assert.Equal(t, 2, rows(t, db, "word_review_items"))
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+    "context"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/srs"
+)
+
+// SchedulerService keeps the spaced repetition schedule of each word up to
+// date and answers which words are due for review.
+type SchedulerService struct {
+    repo      repository.ReviewStore
+    algorithm srs.Algorithm
+    now       func() time.Time
+}
+
+func NewSchedulerService(repo repository.ReviewStore, algorithm srs.Algorithm) *SchedulerService {
+    return &SchedulerService{repo: repo, algorithm: algorithm, now: time.Now}
+}
+
+// With returns a copy of the service that keeps schedules in repo, such as
+// the ReviewStore of a unit of work.
+func (s *SchedulerService) With(repo repository.ReviewStore) *SchedulerService {
+    scheduler := *s
+    scheduler.repo = repo
+    return &scheduler
+}
+
+// RecordReview advances the schedule of a word after it was answered.
+func (s *SchedulerService) RecordReview(ctx context.Context, wordID int64, correct bool) (*models.WordSchedule, error) {
+    state, err := s.repo.GetSchedule(ctx, wordID)
+    if err != nil {
+        return nil, err
+    }
+
+    next := s.algorithm.Schedule(*state, srs.GradeFromCorrect(correct), s.now().UTC())
+    if err := s.repo.SaveSchedule(ctx, &next); err != nil {
+        return nil, err
+    }
+    return &next, nil
+}
+
+// DueWords returns up to limit words that should be reviewed now.
+func (s *SchedulerService) DueWords(ctx context.Context, limit int) ([]models.DueWord, error) {
+    return s.repo.GetDueWords(ctx, s.now().UTC(), limit)
+}
@@ -0,0 +1,87 @@
+package srs
+
+import (
+    "math"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+const (
+    fsrsDecay  = -0.5
+    fsrsFactor = 19.0 / 81.0
+)
+
+// FSRS is version 4 of the Free Spaced Repetition Scheduler.
+type FSRS struct {
+    Weights          [17]float64
+    RequestRetention float64
+    MaximumInterval  float64
+}
+
+func NewFSRS() *FSRS {
+    return &FSRS{
+        Weights: [17]float64{
+            0.4, 0.6, 2.4, 5.8, 4.93, 0.94, 0.86, 0.01, 1.49,
+            0.14, 0.94, 2.18, 0.05, 0.34, 1.26, 0.29, 2.61,
+        },
+        RequestRetention: 0.9,
+        MaximumInterval:  36500,
+    }
+}
+
+func (a *FSRS) Name() string {
+    return "fsrs"
+}
+
+func (a *FSRS) Schedule(state models.WordSchedule, grade Grade, now time.Time) models.WordSchedule {
+    w := a.Weights
+    g := float64(grade)
+
+    if state.Stability == 0 || state.LastReviewedAt == nil {
+        state.Stability = math.Max(w[int(grade)-1], 0.1)
+        state.Difficulty = a.initialDifficulty(g)
+    } else {
+        elapsed := math.Max(now.Sub(*state.LastReviewedAt).Hours()/24, 0)
+        r := math.Pow(1+fsrsFactor*elapsed/state.Stability, fsrsDecay)
+
+        if grade == Again {
+            state.Lapses++
+            state.Stability = w[11] * math.Pow(state.Difficulty, -w[12]) *
+                (math.Pow(state.Stability+1, w[13]) - 1) * math.Exp(w[14]*(1-r))
+        } else {
+            bonus := 1.0
+            if grade == Hard {
+                bonus = w[15]
+            } else if grade == Easy {
+                bonus = w[16]
+            }
+            state.Stability *= 1 + math.Exp(w[8])*(11-state.Difficulty)*
+                math.Pow(state.Stability, -w[9])*(math.Exp(w[10]*(1-r))-1)*bonus
+        }
+
+        next := state.Difficulty - w[6]*(g-3)
+        state.Difficulty = clamp(w[7]*a.initialDifficulty(float64(Good))+(1-w[7])*next, 1, 10)
+    }
+
+    if grade == Again {
+        state.Repetitions = 0
+    } else {
+        state.Repetitions++
+    }
+
+    interval := state.Stability / fsrsFactor * (math.Pow(a.RequestRetention, 1/fsrsDecay) - 1)
+    state.IntervalDays = clamp(math.Round(interval), 1, a.MaximumInterval)
+
+    reviewed := now
+    state.LastReviewedAt = &reviewed
+    state.DueAt = addDays(now, state.IntervalDays)
+    return state
+}
+
+func (a *FSRS) initialDifficulty(g float64) float64 {
+    return clamp(a.Weights[4]-(g-3)*a.Weights[5], 1, 10)
+}
+
+func clamp(v, min, max float64) float64 {
+    return math.Min(math.Max(v, min), max)
+}
@@ -0,0 +1,70 @@
+package srs
+
+import (
+    "math"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+// SM2 is the SuperMemo 2 algorithm.
+type SM2 struct {
+    InitialEase float64
+    MinimumEase float64
+}
+
+func NewSM2() *SM2 {
+    return &SM2{InitialEase: 2.5, MinimumEase: 1.3}
+}
+
+func (a *SM2) Name() string {
+    return "sm2"
+}
+
+func (a *SM2) Schedule(state models.WordSchedule, grade Grade, now time.Time) models.WordSchedule {
+    if state.Ease == 0 {
+        state.Ease = a.InitialEase
+    }
+
+    q := a.quality(grade)
+    if q >= 3 {
+        switch state.Repetitions {
+        case 0:
+            state.IntervalDays = 1
+        case 1:
+            state.IntervalDays = 6
+        default:
+            state.IntervalDays = math.Round(state.IntervalDays * state.Ease)
+        }
+        state.Repetitions++
+    } else {
+        if state.Repetitions > 0 {
+            state.Lapses++
+        }
+        state.Repetitions = 0
+        state.IntervalDays = 1
+    }
+
+    state.Ease += 0.1 - (5-q)*(0.08+(5-q)*0.02)
+    if state.Ease < a.MinimumEase {
+        state.Ease = a.MinimumEase
+    }
+
+    reviewed := now
+    state.LastReviewedAt = &reviewed
+    state.DueAt = addDays(now, state.IntervalDays)
+    return state
+}
+
+// quality converts a grade to SM-2's 0-5 response quality.
+func (a *SM2) quality(grade Grade) float64 {
+    switch grade {
+    case Again:
+        return 1
+    case Hard:
+        return 3
+    case Easy:
+        return 5
+    default:
+        return 4
+    }
+}
@@ -0,0 +1,73 @@
+// Package srs implements the spaced repetition algorithms used to decide
+// when a word should be reviewed again.
+package srs
+
+import (
+    "fmt"
+    "sort"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+// Grade rates how well a word was recalled, on the four-point scale used by
+// FSRS and Anki.
+type Grade int
+
+const (
+    Again Grade = iota + 1
+    Hard
+    Good
+    Easy
+)
+
+// GradeFromCorrect maps the correct/wrong answers recorded by study
+// activities onto the grade scale.
+func GradeFromCorrect(correct bool) Grade {
+    if correct {
+        return Good
+    }
+    return Again
+}
+
+// Algorithm computes the next schedule of a word after a review. state is
+// the zero value (apart from WordID) for a word that was never reviewed.
+type Algorithm interface {
+    Name() string
+    Schedule(state models.WordSchedule, grade Grade, now time.Time) models.WordSchedule
+}
+
+// DefaultAlgorithm is used when no algorithm is configured.
+const DefaultAlgorithm = "sm2"
+
+var algorithms = map[string]func() Algorithm{
+    "sm2":  func() Algorithm { return NewSM2() },
+    "fsrs": func() Algorithm { return NewFSRS() },
+}
+
+// New returns the algorithm registered under name; an empty name selects
+// DefaultAlgorithm.
+func New(name string) (Algorithm, error) {
+    if name == "" {
+        name = DefaultAlgorithm
+    }
+    newAlgorithm, ok := algorithms[name]
+    if !ok {
+        return nil, fmt.Errorf("unknown spaced repetition algorithm %q (available: %v)", name, Names())
+    }
+    return newAlgorithm(), nil
+}
+
+// Names lists the registered algorithms.
+func Names() []string {
+    names := make([]string, 0, len(algorithms))
+    for name := range algorithms {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// addDays moves t forward by a possibly fractional number of days.
+func addDays(t time.Time, days float64) time.Time {
+    return t.Add(time.Duration(days * float64(24*time.Hour)))
+}
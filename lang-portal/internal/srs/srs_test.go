@@ -0,0 +1,72 @@
+package srs
+
+import (
+    "testing"
+    "time"
+    "github.com/stretchr/testify/assert"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+func TestNew(t *testing.T) {
+    algorithm, err := New("")
+    assert.NoError(t, err)
+    assert.Equal(t, DefaultAlgorithm, algorithm.Name())
+
+    algorithm, err = New("fsrs")
+    assert.NoError(t, err)
+    assert.Equal(t, "fsrs", algorithm.Name())
+
+    _, err = New("leitner")
+    assert.Error(t, err)
+}
+
+func TestSM2_Schedule(t *testing.T) {
+    now := time.Date(2025, 2, 8, 12, 0, 0, 0, time.UTC)
+    sm2 := NewSM2()
+
+    state := sm2.Schedule(models.WordSchedule{WordID: 1}, Good, now)
+    assert.Equal(t, 1.0, state.IntervalDays)
+    assert.Equal(t, 1, state.Repetitions)
+    assert.Equal(t, now.AddDate(0, 0, 1), state.DueAt)
+
+    state = sm2.Schedule(state, Good, state.DueAt)
+    assert.Equal(t, 6.0, state.IntervalDays)
+
+    state = sm2.Schedule(state, Good, state.DueAt)
+    assert.Equal(t, 15.0, state.IntervalDays)
+    assert.InDelta(t, 2.5, state.Ease, 1e-9)
+
+    state = sm2.Schedule(state, Again, state.DueAt)
+    assert.Equal(t, 1.0, state.IntervalDays)
+    assert.Equal(t, 0, state.Repetitions)
+    assert.Equal(t, 1, state.Lapses)
+    assert.InDelta(t, 1.96, state.Ease, 1e-9)
+
+    for i := 0; i < 10; i++ {
+        state = sm2.Schedule(state, Again, state.DueAt)
+    }
+    assert.Equal(t, sm2.MinimumEase, state.Ease)
+}
+
+func TestFSRS_Schedule(t *testing.T) {
+    now := time.Date(2025, 2, 8, 12, 0, 0, 0, time.UTC)
+    fsrs := NewFSRS()
+
+    state := fsrs.Schedule(models.WordSchedule{WordID: 1}, Good, now)
+    assert.Equal(t, fsrs.Weights[2], state.Stability)
+    assert.Equal(t, fsrs.Weights[4], state.Difficulty)
+    assert.Equal(t, 2.0, state.IntervalDays)
+
+    // Recalling on the due date grows stability and the interval
+    good := fsrs.Schedule(state, Good, state.DueAt)
+    assert.Greater(t, good.Stability, state.Stability)
+    assert.Greater(t, good.IntervalDays, state.IntervalDays)
+    assert.Equal(t, 0, good.Lapses)
+
+    // Forgetting shrinks stability, raises difficulty and counts a lapse
+    again := fsrs.Schedule(state, Again, state.DueAt)
+    assert.Less(t, again.Stability, state.Stability)
+    assert.Greater(t, again.Difficulty, state.Difficulty)
+    assert.Equal(t, 1, again.Lapses)
+    assert.Equal(t, 1.0, again.IntervalDays)
+}
@@ -0,0 +1,41 @@
+// Package testdb opens the in-memory SQLite databases the tests run
+// against.
+package testdb
+
+import (
+    "context"
+    "database/sql"
+    "testing"
+    "github.com/karl247ai/lang-portal/internal/migrate"
+    "github.com/karl247ai/lang-portal/migrations"
+    _ "github.com/mattn/go-sqlite3"
+)
+
+// Empty returns an empty in-memory database, closed when the test ends.
+func Empty(t testing.TB) *sql.DB {
+    t.Helper()
+    db, err := sql.Open("sqlite3", ":memory:")
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // Every connection to :memory: gets its own empty database
+    db.SetMaxOpenConns(1)
+    t.Cleanup(func() { db.Close() })
+    return db
+}
+
+// Open returns an in-memory database with every migration applied, closed
+// when the test ends.
+func Open(t testing.TB) *sql.DB {
+    t.Helper()
+    db := Empty(t)
+    migrator, err := migrate.New(db, migrations.FS)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := migrator.Up(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+    return db
+}
@@ -0,0 +1,46 @@
+// Package textnorm reduces word text to a canonical form in which spellings
+// a learner would consider the same word compare equal. It is used to find
+// duplicate words, not for display.
+package textnorm
+
+import (
+    "strings"
+    "unicode"
+    "golang.org/x/text/unicode/norm"
+)
+
+// Width folds full-width ASCII and half-width kana to their usual width
+// (NFKC), composing a half-width voiced mark with its kana so that ｶﾞ
+// becomes ガ.
+func Width(s string) string {
+    return norm.NFKC.String(s)
+}
+
+// Japanese normalizes Japanese text: full-width ASCII and half-width kana
+// are folded to their usual width (NFKC), katakana becomes hiragana, Latin
+// letters are lower-cased and whitespace is dropped.
+func Japanese(s string) string {
+    s = Width(s)
+
+    var b strings.Builder
+    for _, r := range s {
+        switch {
+        case unicode.IsSpace(r):
+            continue
+        case r >= 0x30A1 && r <= 0x30F6:
+            // Katakana to hiragana; ー and the rare ヷヸヹヺ have no
+            // hiragana form and are kept
+            r -= 0x60
+        default:
+            r = unicode.ToLower(r)
+        }
+        b.WriteRune(r)
+    }
+    return b.String()
+}
+
+// English normalizes English text: widths are folded (NFKC), letters are
+// lower-cased and runs of whitespace collapse to a single space.
+func English(s string) string {
+    return strings.Join(strings.Fields(strings.ToLower(Width(s))), " ")
+}
@@ -0,0 +1,28 @@
+package textnorm
+
+import (
+    "testing"
+    "github.com/stretchr/testify/assert"
+)
+
+func TestJapanese(t *testing.T) {
+    for in, want := range map[string]string{
+        "ネコ": "ねこ",
+        "ﾈｺ": "ねこ",
+        "ねこ": "ねこ",
+        " 猫 ": "猫",
+        "コーヒー": "こーひー",
+        "ＴＶ番組": "tv番組",
+        "お 茶": "お茶",
+        "ガッコウ": "がっこう",
+        "ﾊﾟﾝ": "ぱん",
+    } {
+        assert.Equal(t, want, Japanese(in), in)
+    }
+}
+
+func TestEnglish(t *testing.T) {
+    assert.Equal(t, "to eat", English("  To   EAT "))
+    assert.Equal(t, "cat", English("ｃａｔ"))
+    assert.Equal(t, "", English("   "))
+}
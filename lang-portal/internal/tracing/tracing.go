@@ -0,0 +1,137 @@
+// Package tracing exports OpenTelemetry traces: a span per HTTP request,
+// named after its route, with a child span per repository method.
+package tracing
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "github.com/gin-gonic/gin"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+    "github.com/karl247ai/lang-portal/internal/repository"
+)
+
+// Exporters accepted by Setup.
+const (
+    ExporterNone   = "none"
+    ExporterStdout = "stdout"
+    ExporterOTLP   = "otlp"
+)
+
+// instrumentation names the tracer the spans of this package come from.
+const instrumentation = "github.com/karl247ai/lang-portal/internal/tracing"
+
+// Setup installs the global tracer provider, exporting spans to stdout or
+// over OTLP/HTTP to endpoint, and returns the function that flushes and
+// stops it. With ExporterNone nothing is installed and every span is a
+// no-op. Incoming W3C trace context headers are honoured either way.
+func Setup(ctx context.Context, exporter, endpoint, serviceName, version string) (shutdown func(context.Context) error, err error) {
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+    var exp sdktrace.SpanExporter
+    switch exporter {
+    case ExporterNone:
+        return func(context.Context) error { return nil }, nil
+    case ExporterStdout:
+        exp, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+    case ExporterOTLP:
+        exp, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+    default:
+        return nil, fmt.Errorf("unknown trace exporter %q", exporter)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    res, err := resource.New(ctx,
+        resource.WithTelemetrySDK(),
+        resource.WithAttributes(
+            attribute.String("service.name", serviceName),
+            attribute.String("service.version", version),
+        ),
+    )
+    if err != nil {
+        return nil, err
+    }
+
+    provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(res))
+    otel.SetTracerProvider(provider)
+    return provider.Shutdown, nil
+}
+
+// Middleware starts a server span for every request, named after its route
+// template such as "GET /api/v1/words/:id", and puts it in the request
+// context so repository spans become its children.
+func Middleware() gin.HandlerFunc {
+    tracer := otel.Tracer(instrumentation)
+    return func(c *gin.Context) {
+        ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+        route := c.FullPath()
+        name := c.Request.Method
+        if route != "" {
+            name += " " + route
+        }
+        ctx, span := tracer.Start(ctx, name,
+            trace.WithSpanKind(trace.SpanKindServer),
+            trace.WithAttributes(
+                attribute.String("http.request.method", c.Request.Method),
+                attribute.String("http.route", route),
+                attribute.String("url.path", c.Request.URL.Path),
+            ),
+        )
+        defer span.End()
+        if id := c.GetString("request_id"); id != "" {
+            span.SetAttributes(attribute.String("request_id", id))
+        }
+
+        c.Request = c.Request.WithContext(ctx)
+        c.Next()
+
+        status := c.Writer.Status()
+        span.SetAttributes(attribute.Int("http.response.status_code", status))
+        if status >= http.StatusInternalServerError {
+            if len(c.Errors) > 0 {
+                span.RecordError(c.Errors.Last().Err)
+            }
+            span.SetStatus(codes.Error, http.StatusText(status))
+        }
+    }
+}
+
+// TraceQueries starts a span for every repository method from now on.
+func TraceQueries() {
+    repository.Observe(queryTracer{tracer: otel.Tracer(instrumentation)})
+}
+
+// queryTracer spans repository methods, naming them after the statement
+// they run, such as words.GetWords. Methods reporting their rows, those of
+// WordRepository, add the count as db.rows.
+type queryTracer struct {
+    tracer trace.Tracer
+}
+
+func (q queryTracer) ObserveQuery(ctx context.Context, repo, method string) (context.Context, func()) {
+    name := repo + "." + method
+    ctx, span := q.tracer.Start(ctx, name,
+        trace.WithSpanKind(trace.SpanKindClient),
+        trace.WithAttributes(
+            attribute.String("db.system", "sqlite"),
+            attribute.String("db.statement.name", name),
+            attribute.String("db.operation.name", method),
+        ),
+    )
+    return ctx, func() { span.End() }
+}
+
+func (q queryTracer) ObserveRows(ctx context.Context, rows int64) {
+    trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("db.rows", rows))
+}
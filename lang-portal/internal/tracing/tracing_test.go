@@ -0,0 +1,73 @@
+package tracing
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "github.com/gin-gonic/gin"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+    m := map[attribute.Key]attribute.Value{}
+    for _, kv := range span.Attributes() {
+        m[kv.Key] = kv.Value
+    }
+    return m
+}
+
+func TestMiddleware(t *testing.T) {
+    recorder := tracetest.NewSpanRecorder()
+    otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+    _, err := Setup(context.Background(), ExporterNone, "", "lang-portal", "test")
+    require.NoError(t, err)
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.Use(Middleware())
+    tracer := queryTracer{tracer: otel.Tracer(instrumentation)}
+    r.GET("/words/:id", func(c *gin.Context) {
+        ctx, done := tracer.ObserveQuery(c.Request.Context(), "words", "GetWord")
+        tracer.ObserveRows(ctx, 1)
+        done()
+        c.Error(errors.New("disk full"))
+        c.Status(http.StatusInternalServerError)
+    })
+
+    req := httptest.NewRequest(http.MethodGet, "/words/7", nil)
+    req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+    r.ServeHTTP(httptest.NewRecorder(), req)
+
+    spans := recorder.Ended()
+    require.Len(t, spans, 2)
+    query, server := spans[0], spans[1]
+
+    assert.Equal(t, "words.GetWord", query.Name())
+    assert.Equal(t, server.SpanContext().SpanID(), query.Parent().SpanID())
+    assert.Equal(t, int64(1), attrs(query)["db.rows"].AsInt64())
+    assert.Equal(t, "words.GetWord", attrs(query)["db.statement.name"].AsString())
+
+    // The incoming trace context is continued
+    assert.Equal(t, "GET /words/:id", server.Name())
+    assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+    assert.Equal(t, "/words/:id", attrs(server)["http.route"].AsString())
+    assert.Equal(t, int64(500), attrs(server)["http.response.status_code"].AsInt64())
+    assert.Equal(t, codes.Error, server.Status().Code)
+}
+
+func TestSetup(t *testing.T) {
+    shutdown, err := Setup(context.Background(), ExporterNone, "", "lang-portal", "test")
+    require.NoError(t, err)
+    assert.NoError(t, shutdown(context.Background()))
+
+    _, err = Setup(context.Background(), "jaeger", "", "lang-portal", "test")
+    assert.EqualError(t, err, `unknown trace exporter "jaeger"`)
+}
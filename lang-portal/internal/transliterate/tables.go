@@ -0,0 +1,65 @@
+package transliterate
+
+// hepburn maps hiragana, and the digraphs written with small kana, to
+// modern Hepburn. Katakana is folded to hiragana before lookup.
+var hepburn = map[string]string{
+    "あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+    "か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+    "が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+    "さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+    "ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+    "た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+    "だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+    "な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+    "は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+    "ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+    "ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+    "ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+    "や": "ya", "ゆ": "yu", "よ": "yo",
+    "ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+    "わ": "wa", "ゐ": "i", "ゑ": "e", "を": "o", "ん": "n", "ゔ": "vu",
+    "ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+    "ゃ": "ya", "ゅ": "yu", "ょ": "yo", "ゎ": "wa", "ゕ": "ka", "ゖ": "ke",
+
+    "きゃ": "kya", "きゅ": "kyu", "きょ": "kyo", "ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+    "しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+    "じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+    "ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+    "ぢゃ": "ja", "ぢゅ": "ju", "ぢょ": "jo",
+    "にゃ": "nya", "にゅ": "nyu", "にょ": "nyo", "ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+    "びゃ": "bya", "びゅ": "byu", "びょ": "byo", "ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+    "みゃ": "mya", "みゅ": "myu", "みょ": "myo", "りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+    "ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo", "ふゅ": "fyu",
+    "てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du", "でゅ": "dyu",
+    "うぃ": "wi", "うぇ": "we", "うぉ": "wo", "いぇ": "ye",
+    "ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+    "つぁ": "tsa", "つぃ": "tsi", "つぇ": "tse", "つぉ": "tso",
+    "くぁ": "kwa", "ぐぁ": "gwa",
+
+    // Katakana without a hiragana counterpart
+    "ヷ": "va", "ヸ": "vi", "ヹ": "ve", "ヺ": "vo",
+}
+
+// kunrei lists where Kunrei-shiki differs from Hepburn.
+var kunrei = map[string]string{
+    "し": "si", "じ": "zi", "ち": "ti", "つ": "tu", "ぢ": "zi", "ふ": "hu",
+    "しゃ": "sya", "しゅ": "syu", "しょ": "syo", "しぇ": "sye",
+    "じゃ": "zya", "じゅ": "zyu", "じょ": "zyo", "じぇ": "zye",
+    "ちゃ": "tya", "ちゅ": "tyu", "ちょ": "tyo", "ちぇ": "tye",
+    "ぢゃ": "zya", "ぢゅ": "zyu", "ぢょ": "zyo",
+    "ふぁ": "hwa", "ふぃ": "hwi", "ふぇ": "hwe", "ふぉ": "hwo", "ふゅ": "hwyu",
+    "つぁ": "tua", "つぃ": "tui", "つぇ": "tue", "つぉ": "tuo",
+}
+
+// nihonShiki lists where Nihon-shiki differs from Kunrei-shiki: it keeps
+// the d row and the historical w kana distinct.
+var nihonShiki = map[string]string{
+    "ぢ": "di", "づ": "du", "ぢゃ": "dya", "ぢゅ": "dyu", "ぢょ": "dyo",
+    "ゐ": "wi", "ゑ": "we", "を": "wo", "くぁ": "kwa", "ぐぁ": "gwa",
+}
+
+// macrons and circumflexes mark long vowels in the respective systems.
+var (
+    macron     = map[byte]string{'a': "ā", 'i': "ī", 'u': "ū", 'e': "ē", 'o': "ō"}
+    circumflex = map[byte]string{'a': "â", 'i': "î", 'u': "û", 'e': "ê", 'o': "ô"}
+)
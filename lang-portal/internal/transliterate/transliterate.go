@@ -0,0 +1,202 @@
+// Package transliterate converts hiragana and katakana to romaji in the
+// Hepburn, Kunrei-shiki and Nihon-shiki systems.
+package transliterate
+
+import (
+    "fmt"
+    "strings"
+    "unicode/utf8"
+    "github.com/karl247ai/lang-portal/internal/textnorm"
+)
+
+// System is a romanization system.
+type System string
+
+const (
+    Hepburn    System = "hepburn"
+    Kunrei     System = "kunrei"
+    NihonShiki System = "nihon-shiki"
+)
+
+// Systems lists the supported systems, Hepburn first.
+var Systems = []System{Hepburn, Kunrei, NihonShiki}
+
+var tables = map[System]map[string]string{}
+
+func init() {
+    tables[Hepburn] = hepburn
+    tables[Kunrei] = merge(hepburn, kunrei)
+    tables[NihonShiki] = merge(tables[Kunrei], nihonShiki)
+}
+
+func merge(base, overrides map[string]string) map[string]string {
+    table := make(map[string]string, len(base))
+    for k, v := range base {
+        table[k] = v
+    }
+    for k, v := range overrides {
+        table[k] = v
+    }
+    return table
+}
+
+// ParseSystem validates a system name; an empty name selects Hepburn.
+func ParseSystem(name string) (System, error) {
+    if name == "" {
+        return Hepburn, nil
+    }
+    for _, s := range Systems {
+        if string(s) == strings.ToLower(name) {
+            return s, nil
+        }
+    }
+    return "", fmt.Errorf("unknown romanization system %q", name)
+}
+
+// Options controls the output of ToRomaji.
+type Options struct {
+    System System
+    // Macrons marks long vowels with a macron (Hepburn) or circumflex
+    // (Kunrei, Nihon-shiki) instead of writing them out: ō rather than ou.
+    // ei and ii are always written out.
+    Macrons bool
+}
+
+// Error reports a character that is not kana.
+type Error struct {
+    Rune rune
+    // Offset is the 0-based position of Rune in the width-folded input, in
+    // characters.
+    Offset int
+}
+
+func (e *Error) Error() string {
+    return fmt.Sprintf("cannot transliterate %q at character %d, only kana is supported", e.Rune, e.Offset+1)
+}
+
+// ToRomaji transliterates kana. Sokuon doubles the next consonant (tch
+// before ch in Hepburn), ー lengthens the preceding vowel, and syllabic n
+// before a vowel or y is written n'. Half-width katakana and full-width
+// ASCII are width-folded first. Spaces and printable ASCII are copied;
+// anything else, such as kanji, is an *Error.
+func ToRomaji(kana string, opts Options) (string, error) {
+    if opts.System == "" {
+        opts.System = Hepburn
+    }
+    table, ok := tables[opts.System]
+    if !ok {
+        return "", fmt.Errorf("unknown romanization system %q", opts.System)
+    }
+    marks := macron
+    if opts.System != Hepburn {
+        marks = circumflex
+    }
+
+    // Width folding can change the length of the input, so errors report
+    // the folded rune and its position in the folded text
+    folded := []rune(textnorm.Width(kana))
+    runes := make([]rune, len(folded))
+    for i, r := range folded {
+        runes[i] = foldKana(r)
+    }
+
+    var out strings.Builder
+    // last is the final byte written if it is a plain vowel that may still
+    // be lengthened, and 0 otherwise
+    var last byte
+    sokuon := false
+    for i := 0; i < len(runes); i++ {
+        r := runes[i]
+        switch {
+        case r == 'っ':
+            sokuon = true
+            continue
+        case r == 'ー':
+            if last != 0 {
+                lengthen(&out, last, marks, opts.Macrons)
+                last = 0
+            }
+            continue
+        case r == ' ' || r == '　' || r == '・':
+            out.WriteByte(' ')
+            last, sokuon = 0, false
+            continue
+        case r < utf8.RuneSelf && r >= ' ' && r != 0x7f:
+            out.WriteRune(r)
+            last, sokuon = 0, false
+            continue
+        }
+
+        syllable, ok := "", false
+        if i+1 < len(runes) {
+            if syllable, ok = table[string(runes[i:i+2])]; ok {
+                i++
+            }
+        }
+        if !ok {
+            if syllable, ok = table[string(r)]; !ok {
+                return "", &Error{Rune: folded[i], Offset: i}
+            }
+        }
+
+        if sokuon {
+            switch {
+            case opts.System == Hepburn && strings.HasPrefix(syllable, "ch"):
+                out.WriteByte('t')
+            case !isVowel(syllable[0]):
+                out.WriteByte(syllable[0])
+            }
+            sokuon = false
+        }
+
+        if len(syllable) == 1 && isVowel(syllable[0]) && isFullVowel(r) && opts.Macrons && last != 0 &&
+            ((syllable[0] == last && last != 'i') || (syllable[0] == 'u' && last == 'o')) {
+            lengthen(&out, last, marks, true)
+            last = 0
+            continue
+        }
+
+        if r == 'ん' && i+1 < len(runes) {
+            if next, ok := table[string(runes[i+1])]; ok && (isVowel(next[0]) || next[0] == 'y') {
+                syllable = "n'"
+            }
+        }
+        out.WriteString(syllable)
+
+        last = 0
+        if end := syllable[len(syllable)-1]; isVowel(end) {
+            last = end
+        }
+    }
+    return out.String(), nil
+}
+
+// lengthen marks the vowel v just written as long, or repeats it.
+func lengthen(out *strings.Builder, v byte, marks map[byte]string, useMarks bool) {
+    if !useMarks {
+        out.WriteByte(v)
+        return
+    }
+    s := out.String()
+    out.Reset()
+    out.WriteString(s[:len(s)-1])
+    out.WriteString(marks[v])
+}
+
+func isVowel(c byte) bool {
+    return strings.IndexByte("aiueo", c) >= 0
+}
+
+// isFullVowel reports whether r is one of the full-size vowel kana あいうえお,
+// which are the only ones that can lengthen the vowel before them.
+func isFullVowel(r rune) bool {
+    return strings.ContainsRune("あいうえお", r)
+}
+
+// foldKana turns katakana into the matching hiragana.
+func foldKana(r rune) rune {
+    if r >= 0x30A1 && r <= 0x30F6 {
+        return r - 0x60
+    }
+    return r
+}
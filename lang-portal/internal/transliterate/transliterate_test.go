@@ -0,0 +1,121 @@
+package transliterate
+
+import (
+    "testing"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+)
+
+func TestToRomaji(t *testing.T) {
+    tests := []struct {
+        kana    string
+        hepburn string
+        kunrei  string
+        nihon   string
+    }{
+        {"ひらがな", "hiragana", "hiragana", "hiragana"},
+        {"カタカナ", "katakana", "katakana", "katakana"},
+        {"しんぶん", "shinbun", "sinbun", "sinbun"},
+        {"ちず", "chizu", "tizu", "tizu"},
+        {"つづく", "tsuzuku", "tuzuku", "tuduku"},
+        {"ふじさん", "fujisan", "huzisan", "huzisan"},
+        {"はなぢ", "hanaji", "hanazi", "hanadi"},
+        {"きょうと", "kyouto", "kyouto", "kyouto"},
+        {"しゃしん", "shashin", "syasin", "syasin"},
+        {"ちゃいろ", "chairo", "tyairo", "tyairo"},
+        {"がっこう", "gakkou", "gakkou", "gakkou"},
+        {"まっちゃ", "matcha", "mattya", "mattya"},
+        {"きっぷ", "kippu", "kippu", "kippu"},
+        {"しんよう", "shin'you", "sin'you", "sin'you"},
+        {"たんい", "tan'i", "tan'i", "tan'i"},
+        {"ほんを", "hon'o", "hon'o", "honwo"},
+        {"コーヒー", "koohii", "koohii", "koohii"},
+        {"パーティー", "paatii", "paatii", "paatii"},
+        {"ヴァイオリン", "vaiorin", "vaiorin", "vaiorin"},
+        {"ファイル", "fairu", "hwairu", "hwairu"},
+        {"Tシャツ", "Tshatsu", "Tsyatu", "Tsyatu"},
+        {"ねこ と いぬ", "neko to inu", "neko to inu", "neko to inu"},
+        {"ﾈｺ", "neko", "neko", "neko"},
+        {"ｶﾞｯｺｳ", "gakkou", "gakkou", "gakkou"},
+        {"ｺｰﾋｰ", "koohii", "koohii", "koohii"},
+        {"Ｔｼｬﾂ", "Tshatsu", "Tsyatu", "Tsyatu"},
+    }
+
+    for _, tt := range tests {
+        for system, want := range map[System]string{Hepburn: tt.hepburn, Kunrei: tt.kunrei, NihonShiki: tt.nihon} {
+            got, err := ToRomaji(tt.kana, Options{System: system})
+            require.NoError(t, err)
+            assert.Equal(t, want, got, "%s in %s", tt.kana, system)
+        }
+    }
+}
+
+func TestToRomaji_Macrons(t *testing.T) {
+    tests := []struct {
+        kana   string
+        system System
+        want   string
+    }{
+        {"とうきょう", Hepburn, "tōkyō"},
+        {"おおさか", Hepburn, "ōsaka"},
+        {"くうき", Hepburn, "kūki"},
+        {"おかあさん", Hepburn, "okāsan"},
+        {"おにいさん", Hepburn, "oniisan"},
+        {"せんせい", Hepburn, "sensei"},
+        {"コーヒー", Hepburn, "kōhī"},
+        {"とうきょう", Kunrei, "tôkyô"},
+        {"ラーメン", NihonShiki, "râmen"},
+    }
+    for _, tt := range tests {
+        got, err := ToRomaji(tt.kana, Options{System: tt.system, Macrons: true})
+        require.NoError(t, err)
+        assert.Equal(t, tt.want, got, tt.kana)
+    }
+}
+
+func TestToRomaji_NotKana(t *testing.T) {
+    _, err := ToRomaji("ねこ猫", Options{})
+    var nonKana *Error
+    require.ErrorAs(t, err, &nonKana)
+    assert.Equal(t, '猫', nonKana.Rune)
+    assert.Equal(t, 2, nonKana.Offset)
+    assert.EqualError(t, err, "cannot transliterate '猫' at character 3, only kana is supported")
+
+    _, err = ToRomaji("ねこ", Options{System: "wapuro"})
+    assert.Error(t, err)
+}
+
+func TestToRomaji_NotKanaFolded(t *testing.T) {
+    tests := []struct {
+        text   string
+        rune   rune
+        offset int
+    }{
+        // ㌀ folds to アパート, four characters
+        {"㌀漢", '漢', 4},
+        // ｶﾞ folds to ガ, one character
+        {"ｶﾞ漢", '漢', 1},
+        // ㈱ folds to (株), and the parenthesis is copied
+        {"ｶﾞ㈱", '株', 2},
+    }
+    for _, tt := range tests {
+        _, err := ToRomaji(tt.text, Options{})
+        var nonKana *Error
+        require.ErrorAs(t, err, &nonKana, tt.text)
+        assert.Equal(t, tt.rune, nonKana.Rune, tt.text)
+        assert.Equal(t, tt.offset, nonKana.Offset, tt.text)
+    }
+}
+
+func TestParseSystem(t *testing.T) {
+    s, err := ParseSystem("")
+    assert.NoError(t, err)
+    assert.Equal(t, Hepburn, s)
+
+    s, err = ParseSystem("Nihon-Shiki")
+    assert.NoError(t, err)
+    assert.Equal(t, NihonShiki, s)
+
+    _, err = ParseSystem("wapuro")
+    assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package validator
+
+import (
+    "fmt"
+    "unicode"
+    "unicode/utf8"
+)
+
+// IsKana reports whether r is hiragana, katakana (full or half width) or
+// the prolonged sound mark.
+func IsKana(r rune) bool {
+    switch {
+    case r >= 0x3041 && r <= 0x309F: // hiragana
+        return true
+    case r >= 0x30A0 && r <= 0x30FF: // katakana, including ー and ・
+        return true
+    case r >= 0x31F0 && r <= 0x31FF: // katakana phonetic extensions
+        return true
+    case r >= 0xFF66 && r <= 0xFF9F: // half-width katakana
+        return true
+    }
+    return false
+}
+
+// IsKanji reports whether r is a CJK ideograph, including the iteration
+// mark 々.
+func IsKanji(r rune) bool {
+    return unicode.Is(unicode.Han, r)
+}
+
+// IsKanaOnly reports whether s is non-empty and written entirely in kana,
+// ignoring spaces.
+func IsKanaOnly(s string) bool {
+    seen := false
+    for _, r := range s {
+        if unicode.IsSpace(r) {
+            continue
+        }
+        if !IsKana(r) {
+            return false
+        }
+        seen = true
+    }
+    return seen
+}
+
+// isJapanesePunct reports whether r is CJK punctuation such as 「」、。〜
+// or a full-width form such as ！ or Ａ.
+func isJapanesePunct(r rune) bool {
+    return (r >= 0x3000 && r <= 0x303F) || (r >= 0xFF01 && r <= 0xFF65)
+}
+
+// checkJapanese returns a problem description when s is not Japanese text:
+// it must contain kana or kanji, and may otherwise only use Japanese
+// punctuation and printable ASCII, as in "Tシャツ".
+func checkJapanese(s string) string {
+    if !utf8.ValidString(s) {
+        return "must be valid UTF-8"
+    }
+
+    script := false
+    for _, r := range s {
+        switch {
+        case IsKana(r) || IsKanji(r):
+            script = true
+        case isJapanesePunct(r):
+        case r < utf8.RuneSelf && unicode.IsPrint(r):
+        default:
+            return fmt.Sprintf("must be Japanese text, found %q", r)
+        }
+    }
+    if !script {
+        return "must contain hiragana, katakana or kanji"
+    }
+    return ""
+}
@@ -0,0 +1,143 @@
+package validator
+
+import (
+    "fmt"
+    "strings"
+    "unicode/utf8"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+// MaxExampleLength limits each side of an example sentence, in characters.
+const MaxExampleLength = 500
+
+// validateParts checks the structured parts of word, reporting problems
+// through fail under keys such as "parts.components[0].reading". Text
+// fields are trimmed and part_of_speech lower-cased first.
+func validateParts(word *models.Word, fail func(name, problem string)) {
+    p := word.Parts
+    if p == nil {
+        return
+    }
+
+    p.Reading = strings.TrimSpace(p.Reading)
+    if p.Reading != "" && !IsKanaOnly(p.Reading) {
+        fail("parts.reading", "must be written in hiragana or katakana")
+    }
+
+    for i := range p.Components {
+        c := &p.Components[i]
+        key := fmt.Sprintf("parts.components[%d]", i)
+        c.Kanji = strings.TrimSpace(c.Kanji)
+        c.Reading = strings.TrimSpace(c.Reading)
+
+        switch {
+        case c.Kanji == "":
+            fail(key+".kanji", "required field")
+        case !strings.Contains(word.Japanese, c.Kanji):
+            fail(key+".kanji", "must be part of japanese")
+        }
+        if c.Reading != "" && !IsKanaOnly(c.Reading) {
+            fail(key+".reading", "must be written in hiragana or katakana")
+        }
+        for _, romaji := range c.Romaji {
+            if problem := checkRomaji(romaji); romaji == "" || problem != "" {
+                if problem == "" {
+                    problem = "must not contain empty syllables"
+                }
+                fail(key+".romaji", problem)
+                break
+            }
+        }
+    }
+
+    p.PartOfSpeech = strings.ToLower(strings.TrimSpace(p.PartOfSpeech))
+    if p.PartOfSpeech != "" && !contains(models.PartsOfSpeech, p.PartOfSpeech) {
+        fail("parts.part_of_speech", "must be one of "+strings.Join(models.PartsOfSpeech, ", "))
+    }
+
+    if p.JLPTLevel != 0 && (p.JLPTLevel < 1 || p.JLPTLevel > 5) {
+        fail("parts.jlpt_level", "must be from 1 (N1) to 5 (N5)")
+    }
+
+    for i := range p.Examples {
+        e := &p.Examples[i]
+        key := fmt.Sprintf("parts.examples[%d]", i)
+        e.Japanese = strings.TrimSpace(e.Japanese)
+        e.English = strings.TrimSpace(e.English)
+
+        switch {
+        case e.Japanese == "":
+            fail(key+".japanese", "required field")
+        case utf8.RuneCountInString(e.Japanese) > MaxExampleLength:
+            fail(key+".japanese", fmt.Sprintf("must be at most %d characters", MaxExampleLength))
+        default:
+            if problem := checkJapanese(e.Japanese); problem != "" {
+                fail(key+".japanese", problem)
+            }
+        }
+        switch {
+        case e.English == "":
+            fail(key+".english", "required field")
+        case utf8.RuneCountInString(e.English) > MaxExampleLength:
+            fail(key+".english", fmt.Sprintf("must be at most %d characters", MaxExampleLength))
+        }
+    }
+
+    if p.PitchAccent != nil {
+        reading, _, _ := partsReading(p)
+        if reading == "" && IsKanaOnly(word.Japanese) {
+            reading = word.Japanese
+        }
+        switch morae := countMorae(reading); {
+        case *p.PitchAccent < 0:
+            fail("parts.pitch_accent", "must not be negative")
+        case IsKanaOnly(reading) && *p.PitchAccent > morae:
+            fail("parts.pitch_accent", fmt.Sprintf("must be between 0 and %d, the number of morae in %s", morae, reading))
+        }
+    }
+}
+
+// partsReading returns the kana reading of the word held in parts, and the
+// field it came from: the reading of the whole word, or else the readings
+// of the components joined in order when every component has one.
+func partsReading(p *models.Parts) (reading, field string, ok bool) {
+    if p == nil {
+        return "", "", false
+    }
+    if p.Reading != "" {
+        return p.Reading, "parts.reading", true
+    }
+    if len(p.Components) == 0 {
+        return "", "", false
+    }
+
+    var b strings.Builder
+    for _, c := range p.Components {
+        if c.Reading == "" {
+            return "", "", false
+        }
+        b.WriteString(c.Reading)
+    }
+    return b.String(), "parts.components", true
+}
+
+// countMorae counts the morae of a kana reading. Small kana combine with
+// the kana before them; っ, ん and ー are morae of their own.
+func countMorae(reading string) int {
+    n := 0
+    for _, r := range reading {
+        if IsKana(r) && !strings.ContainsRune("ゃゅょぁぃぅぇぉゎャュョァィゥェォヮ", r) {
+            n++
+        }
+    }
+    return n
+}
+
+func contains(list []string, s string) bool {
+    for _, v := range list {
+        if v == s {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,138 @@
+package validator
+
+import (
+    "fmt"
+    "strings"
+)
+
+// hepburnSyllables are the syllables of modern Hepburn, including the
+// combinations used to write loanwords (fa, ti, she, ...).
+var hepburnSyllables = map[string]bool{}
+
+// kunreiSyllables maps Kunrei and Nihon-shiki spellings that Hepburn writes
+// differently to their Hepburn form, for error messages.
+var kunreiSyllables = map[string]string{
+    "si": "shi", "zi": "ji", "hu": "fu",
+    "sya": "sha", "syu": "shu", "syo": "sho",
+    "zya": "ja", "zyu": "ju", "zyo": "jo",
+    "tya": "cha", "tyu": "chu", "tyo": "cho",
+    "dya": "ja", "dyo": "jo", "dzu": "zu",
+}
+
+func init() {
+    for _, s := range strings.Fields(`
+        a i u e o
+        ka ki ku ke ko ga gi gu ge go
+        sa shi su se so za ji zu ze zo
+        ta chi tsu te to da de do
+        na ni nu ne no ha hi fu he ho
+        ba bi bu be bo pa pi pu pe po
+        ma mi mu me mo ya yu yo
+        ra ri ru re ro wa wo
+        kya kyu kyo gya gyu gyo sha shu sho ja ju jo
+        cha chu cho nya nyu nyo hya hyu hyo
+        bya byu byo pya pyu pyo mya myu myo rya ryu ryo
+        she je che ti di tu du dyu fa fi fe fo fyu
+        va vi vu ve vo wi we ye tsa tsi tse tso kwa gwa`) {
+        hepburnSyllables[s] = true
+    }
+}
+
+func isVowel(c byte) bool {
+    return strings.IndexByte("aiueo", c) >= 0
+}
+
+// checkRomaji returns a problem description when s is not ASCII Hepburn
+// romaji. Words may be separated by spaces or hyphens, and an apostrophe
+// may follow a syllabic n as in "kan'i". Case is ignored.
+func checkRomaji(s string) string {
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        if c >= 0x80 || !(c == ' ' || c == '-' || c == '\'' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')) {
+            return "must only contain ASCII letters, spaces, hyphens and apostrophes"
+        }
+    }
+
+    for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == ' ' || r == '-' }) {
+        if problem := checkHepburnWord(word); problem != "" {
+            return problem
+        }
+    }
+    return ""
+}
+
+func checkHepburnWord(w string) string {
+    for i := 0; i < len(w); {
+        c := w[i]
+        var next byte
+        if i+1 < len(w) {
+            next = w[i+1]
+        }
+
+        switch {
+        case c == '\'':
+            if i == 0 || w[i-1] != 'n' {
+                return "may only use an apostrophe after a syllabic n"
+            }
+            i++
+            continue
+        case c == 'n' && !isVowel(next) && next != 'y':
+            // Syllabic n
+            i++
+            continue
+        case c == 'm' && (next == 'b' || next == 'm' || next == 'p'):
+            // Traditional Hepburn writes syllabic n as m before b, m and p
+            i++
+            continue
+        case c == 't' && strings.HasPrefix(w[i+1:], "ch"):
+            // Sokuon before ch is written tch
+            i++
+            continue
+        case c == next && strings.IndexByte("bdfghjkpstvz", c) >= 0:
+            // Sokuon doubles the following consonant
+            i++
+            continue
+        }
+
+        matched := false
+        for n := 3; n >= 1 && !matched; n-- {
+            if i+n > len(w) {
+                continue
+            }
+            syllable := w[i : i+n]
+            if hepburn, ok := kunreiSyllables[syllable]; ok {
+                return fmt.Sprintf("must be Hepburn romaji, write %q instead of %q", hepburn, syllable)
+            }
+            if hepburnSyllables[syllable] {
+                i += n
+                matched = true
+            }
+        }
+        if !matched {
+            return fmt.Sprintf("must be Hepburn romaji, %q is not a valid syllable", w[i:])
+        }
+    }
+    return ""
+}
+
+// comparableRomaji reduces romaji to a form in which the common ASCII
+// spellings of one reading compare equal: long vowels may be doubled,
+// written with u or left out (toukyou, tookyoo, tokyo), syllabic n may be
+// written m, and を may be written wo.
+func comparableRomaji(s string) string {
+    s = strings.ToLower(s)
+    s = strings.NewReplacer(" ", "", "-", "", "'", "", "wo", "o", "cch", "tch", "mb", "nb", "mp", "np", "mm", "nm").Replace(s)
+
+    var out []byte
+    for i := 0; i < len(s); i++ {
+        c := s[i]
+        if len(out) > 0 && isVowel(c) {
+            prev := out[len(out)-1]
+            if prev == c || (prev == 'o' && c == 'u') {
+                continue
+            }
+        }
+        out = append(out, c)
+    }
+    return string(out)
+}
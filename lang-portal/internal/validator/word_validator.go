@@ -1,20 +1,125 @@
 package validator
 
 import (
+    "fmt"
     "github.com/karl247ai/lang-portal/internal/models"
-    "errors"
+    "github.com/karl247ai/lang-portal/internal/repository"
+    "github.com/karl247ai/lang-portal/internal/transliterate"
     "strings"
+    "unicode/utf8"
 )
 
+// Field length limits, counted in characters.
+const (
+    MaxJapaneseLength = 100
+    MaxRomajiLength   = 100
+    MaxEnglishLength  = 200
+)
+
+// ValidateWord checks a word before it is written: japanese must be
+// Japanese text, romaji ASCII Hepburn, and both at most 100 characters.
+// When the reading is known, from a kana-only japanese or a kana reading
+// in parts, romaji must transliterate it. Parts are checked field by field.
+// Surrounding whitespace is trimmed from the text fields first.
+//
+// The returned error is a repository validation error listing every
+// failing field.
 func ValidateWord(word *models.Word) error {
-    if strings.TrimSpace(word.Japanese) == "" {
-        return errors.New("japanese is required")
+    word.Japanese = strings.TrimSpace(word.Japanese)
+    word.Romaji = strings.TrimSpace(word.Romaji)
+    word.English = strings.TrimSpace(word.English)
+
+    fields := map[string]string{}
+    var problems []string
+    fail := func(name, problem string) {
+        fields[name] = problem
+        if problem == "required field" {
+            problems = append(problems, name+" is required")
+        } else {
+            problems = append(problems, name+" "+problem)
+        }
+    }
+    check := func(name, value string, max int, rule func(string) string) bool {
+        switch {
+        case value == "":
+            fail(name, "required field")
+        case utf8.RuneCountInString(value) > max:
+            fail(name, fmt.Sprintf("must be at most %d characters", max))
+        case rule != nil:
+            if problem := rule(value); problem != "" {
+                fail(name, problem)
+                return false
+            }
+            return true
+        }
+        return false
+    }
+
+    japaneseOK := check("japanese", word.Japanese, MaxJapaneseLength, checkJapanese)
+    romajiOK := check("romaji", word.Romaji, MaxRomajiLength, checkRomaji)
+    check("english", word.English, MaxEnglishLength, func(s string) string {
+        if !utf8.ValidString(s) {
+            return "must be valid UTF-8"
+        }
+        return ""
+    })
+
+    validateParts(word, fail)
+
+    reading, readingField := "", ""
+    if japaneseOK && IsKanaOnly(word.Japanese) {
+        reading, readingField = word.Japanese, "japanese"
     }
-    if strings.TrimSpace(word.Romaji) == "" {
-        return errors.New("romaji is required")
+    if r, field, ok := partsReading(word.Parts); ok && IsKanaOnly(r) {
+        reading, readingField = r, field
+    }
+
+    if romajiOK && reading != "" {
+        expected, err := transliterate.ToRomaji(reading, transliterate.Options{System: transliterate.Hepburn})
+        if err == nil && !readingMatches(reading, expected, word.Romaji) {
+            fail("romaji", fmt.Sprintf("does not match the %s reading %s (%s)", readingField, reading, expected))
+        }
     }
-    if strings.TrimSpace(word.English) == "" {
-        return errors.New("english is required")
+
+    if len(problems) > 0 {
+        return repository.Invalid(strings.Join(problems, ", "), fields)
     }
     return nil
-}
\ No newline at end of file
+}
+
+// FillRomaji sets an empty romaji to the Hepburn transliteration of the
+// word's kana reading: the reading in parts, or japanese itself when it is
+// written in kana. Words without a kana reading are left unchanged.
+func FillRomaji(word *models.Word) {
+    if strings.TrimSpace(word.Romaji) != "" {
+        return
+    }
+
+    reading, _, ok := partsReading(word.Parts)
+    if !ok || !IsKanaOnly(reading) {
+        reading = strings.TrimSpace(word.Japanese)
+    }
+    if !IsKanaOnly(reading) {
+        return
+    }
+    if romaji, err := transliterate.ToRomaji(reading, transliterate.Options{System: transliterate.Hepburn}); err == nil {
+        word.Romaji = romaji
+    }
+}
+
+// readingMatches reports whether romaji spells the kana reading whose
+// Hepburn transliteration is expected. A trailing は or へ may be read as
+// the particle, as in konnichiwa.
+func readingMatches(reading, expected, romaji string) bool {
+    got := comparableRomaji(romaji)
+    if comparableRomaji(expected) == got {
+        return true
+    }
+    switch {
+    case strings.HasSuffix(reading, "は"):
+        return comparableRomaji(strings.TrimSuffix(expected, "ha")+"wa") == got
+    case strings.HasSuffix(reading, "へ"):
+        return comparableRomaji(strings.TrimSuffix(expected, "he")+"e") == got
+    }
+    return false
+}
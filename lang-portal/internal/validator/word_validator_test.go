@@ -0,0 +1,202 @@
+package validator
+
+import (
+    "encoding/json"
+    "errors"
+    "strings"
+    "testing"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/karl247ai/lang-portal/internal/models"
+    "github.com/karl247ai/lang-portal/internal/repository"
+)
+
+func parts(data string) *models.Parts {
+    var p models.Parts
+    if err := json.Unmarshal([]byte(data), &p); err != nil {
+        panic(err)
+    }
+    return &p
+}
+
+func fieldErrors(t *testing.T, w models.Word) map[string]string {
+    err := ValidateWord(&w)
+    if err == nil {
+        return nil
+    }
+    var invalid *repository.Error
+    require.True(t, errors.As(err, &invalid))
+    assert.ErrorIs(t, err, repository.ErrValidation)
+    return invalid.Details
+}
+
+func TestValidateWord_Valid(t *testing.T) {
+    words := []models.Word{
+        {Japanese: "猫", Romaji: "neko", English: "cat"},
+        {Japanese: "ねこ", Romaji: "Neko", English: "cat"},
+        {Japanese: "東京", Romaji: "Tokyo", English: "Tokyo", Parts: parts(`{"reading": "とうきょう"}`)},
+        {Japanese: "東京", Romaji: "toukyou", English: "Tokyo", Parts: parts(`{"reading": "とうきょう"}`)},
+        {Japanese: "学校", Romaji: "gakkou", English: "school", Parts: parts(`[{"kanji": "学", "reading": "がっ"}, {"kanji": "校", "reading": "こう"}]`)},
+        {Japanese: "新聞", Romaji: "shimbun", English: "newspaper", Parts: parts(`{"reading": "しんぶん"}`)},
+        {Japanese: "コーヒー", Romaji: "koohii", English: "coffee"},
+        {Japanese: "こんにちは", Romaji: "konnichiwa", English: "hello"},
+        {Japanese: "マッチ", Romaji: "matchi", English: "match"},
+        {Japanese: "単位", Romaji: "tan'i", English: "unit", Parts: parts(`{"reading": "たんい"}`)},
+        {Japanese: "Tシャツ", Romaji: "tii-shatsu", English: "T-shirt"},
+        {Japanese: "払う", Romaji: "harau", English: "to pay", Parts: parts(`[{"kanji": "払", "romaji": ["ha", "ra"]}]`)},
+        {Japanese: "  人々 ", Romaji: " hitobito ", English: "people"},
+    }
+    for _, w := range words {
+        assert.Nil(t, fieldErrors(t, w), w.Japanese)
+    }
+}
+
+func TestValidateWord_TrimsFields(t *testing.T) {
+    w := models.Word{Japanese: " 猫 ", Romaji: "neko\n", English: "\tcat"}
+    require.NoError(t, ValidateWord(&w))
+    assert.Equal(t, models.Word{Japanese: "猫", Romaji: "neko", English: "cat"}, w)
+}
+
+func TestValidateWord_Invalid(t *testing.T) {
+    tests := []struct {
+        name   string
+        word   models.Word
+        fields map[string]string
+    }{
+        {
+            "required",
+            models.Word{Japanese: " ", English: "cat"},
+            map[string]string{"japanese": "required field", "romaji": "required field"},
+        },
+        {
+            "not japanese",
+            models.Word{Japanese: "cat", Romaji: "neko", English: "cat"},
+            map[string]string{"japanese": "must contain hiragana, katakana or kanji"},
+        },
+        {
+            "foreign script",
+            models.Word{Japanese: "猫кот", Romaji: "neko", English: "cat"},
+            map[string]string{"japanese": `must be Japanese text, found 'к'`},
+        },
+        {
+            "non ascii romaji",
+            models.Word{Japanese: "東京", Romaji: "tōkyō", English: "Tokyo"},
+            map[string]string{"romaji": "must only contain ASCII letters, spaces, hyphens and apostrophes"},
+        },
+        {
+            "kunrei romaji",
+            models.Word{Japanese: "新聞", Romaji: "sinbun", English: "newspaper"},
+            map[string]string{"romaji": `must be Hepburn romaji, write "shi" instead of "si"`},
+        },
+        {
+            "not a syllable",
+            models.Word{Japanese: "猫", Romaji: "nekx", English: "cat"},
+            map[string]string{"romaji": `must be Hepburn romaji, "kx" is not a valid syllable`},
+        },
+        {
+            "romaji does not match kana",
+            models.Word{Japanese: "いぬ", Romaji: "neko", English: "dog"},
+            map[string]string{"romaji": "does not match the japanese reading いぬ (inu)"},
+        },
+        {
+            "romaji does not match parts reading",
+            models.Word{Japanese: "犬", Romaji: "neko", English: "dog", Parts: parts(`{"reading": "いぬ"}`)},
+            map[string]string{"romaji": "does not match the parts.reading reading いぬ (inu)"},
+        },
+        {
+            "reading not kana",
+            models.Word{Japanese: "犬", Romaji: "inu", English: "dog", Parts: parts(`{"reading": "inu"}`)},
+            map[string]string{"parts.reading": "must be written in hiragana or katakana"},
+        },
+        {
+            "too long",
+            models.Word{Japanese: strings.Repeat("あ", 101), Romaji: strings.Repeat("a", 101), English: strings.Repeat("a", 201)},
+            map[string]string{
+                "japanese": "must be at most 100 characters",
+                "romaji":   "must be at most 100 characters",
+                "english":  "must be at most 200 characters",
+            },
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            assert.Equal(t, tt.fields, fieldErrors(t, tt.word))
+        })
+    }
+}
+
+func TestValidateWord_Message(t *testing.T) {
+    w := models.Word{Japanese: "猫"}
+    assert.EqualError(t, ValidateWord(&w), "romaji is required, english is required")
+}
+
+func TestFillRomaji(t *testing.T) {
+    tests := []struct {
+        word models.Word
+        want string
+    }{
+        {models.Word{Japanese: "ねこ"}, "neko"},
+        {models.Word{Japanese: "キッチン"}, "kitchin"},
+        {models.Word{Japanese: "ｷｯﾁﾝ"}, "kitchin"},
+        {models.Word{Japanese: "東京", Parts: parts(`{"reading": "とうきょう"}`)}, "toukyou"},
+        {models.Word{Japanese: "猫"}, ""},
+        {models.Word{Japanese: "ねこ", Romaji: "neko-chan"}, "neko-chan"},
+    }
+    for _, tt := range tests {
+        FillRomaji(&tt.word)
+        assert.Equal(t, tt.want, tt.word.Romaji, tt.word.Japanese)
+    }
+
+    w := models.Word{Japanese: "しんよう", English: "trust"}
+    FillRomaji(&w)
+    assert.NoError(t, ValidateWord(&w))
+}
+
+func TestValidateWord_Parts(t *testing.T) {
+    valid := models.Word{Japanese: "学校", Romaji: "gakkou", English: "school", Parts: parts(`{
+        "reading": "がっこう",
+        "components": [{"kanji": "学", "reading": "がっ"}, {"kanji": "校", "reading": "こう", "romaji": ["ko", "u"]}],
+        "part_of_speech": "Noun",
+        "jlpt_level": "N5",
+        "examples": [{"japanese": "学校に行きます。", "english": "I go to school."}],
+        "pitch_accent": 0
+    }`)}
+    require.NoError(t, ValidateWord(&valid))
+    assert.Equal(t, "noun", valid.Parts.PartOfSpeech)
+    assert.Equal(t, models.JLPTLevel(5), valid.Parts.JLPTLevel)
+
+    accent := 9
+    invalid := models.Word{Japanese: "学校", Romaji: "gakkou", English: "school", Parts: &models.Parts{
+        Reading:      "がっこう",
+        Components:   []models.PartComponent{{Kanji: "学", Reading: "gaku"}, {Kanji: "猫"}, {Kanji: " "}},
+        PartOfSpeech: "thing",
+        JLPTLevel:    6,
+        Examples:     []models.Example{{Japanese: "school", English: ""}},
+        PitchAccent:  &accent,
+    }}
+    assert.Equal(t, map[string]string{
+        "parts.components[0].reading": "must be written in hiragana or katakana",
+        "parts.components[1].kanji":   "must be part of japanese",
+        "parts.components[2].kanji":   "required field",
+        "parts.part_of_speech":        "must be one of " + strings.Join(models.PartsOfSpeech, ", "),
+        "parts.jlpt_level":            "must be from 1 (N1) to 5 (N5)",
+        "parts.examples[0].japanese":  "must contain hiragana, katakana or kanji",
+        "parts.examples[0].english":   "required field",
+        "parts.pitch_accent":          "must be between 0 and 4, the number of morae in がっこう",
+    }, fieldErrors(t, invalid))
+}
+
+func TestValidateWord_ComponentReadings(t *testing.T) {
+    w := models.Word{Japanese: "学校", Romaji: "gakusei", English: "school", Parts: parts(`[{"kanji": "学", "reading": "がっ"}, {"kanji": "校", "reading": "こう"}]`)}
+    assert.Equal(t, map[string]string{"romaji": "does not match the parts.components reading がっこう (gakkou)"}, fieldErrors(t, w))
+}
+
+func TestParts_UnmarshalJSON(t *testing.T) {
+    var p models.Parts
+    assert.ErrorContains(t, json.Unmarshal([]byte(`{"part_of_speach": "noun"}`), &p), `unknown field "part_of_speach"`)
+    assert.Error(t, json.Unmarshal([]byte(`{"jlpt_level": "beginner"}`), &p))
+
+    require.NoError(t, json.Unmarshal([]byte(`[{"kanji": "払", "romaji": ["ha", "ra"]}]`), &p))
+    assert.Equal(t, models.Parts{Components: []models.PartComponent{{Kanji: "払", Romaji: []string{"ha", "ra"}}}}, p)
+}
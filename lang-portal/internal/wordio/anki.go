@@ -0,0 +1,555 @@
+package wordio
+
+import (
+    "archive/zip"
+    "bufio"
+    "crypto/sha1"
+    "database/sql"
+    "encoding/csv"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "html"
+    "io"
+    "os"
+    "path/filepath"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+    "github.com/karl247ai/lang-portal/internal/models"
+    _ "github.com/mattn/go-sqlite3"
+)
+
+// ankiFieldNames maps the note field names commonly used in Japanese decks
+// to word fields. A reading field holds kana, often as furigana, so it goes
+// to the parts reading and romaji is transliterated from it.
+var ankiFieldNames = map[string]string{
+    "japanese":   "japanese",
+    "kanji":      "japanese",
+    "expression": "japanese",
+    "word":       "japanese",
+    "vocab":      "japanese",
+    "front":      "japanese",
+    "romaji":     "romaji",
+    "reading":    "reading",
+    "kana":       "reading",
+    "english":    "english",
+    "meaning":    "english",
+    "definition": "english",
+    "back":       "english",
+    "parts":      "parts",
+}
+
+// ankiFields locates the word fields among a note's field names. Unknown
+// names fall back to position: japanese, romaji, english, or japanese and
+// english for two-field notes.
+func ankiFields(names []string, count int) map[string]int {
+    idx := map[string]int{}
+    for i, name := range names {
+        if field, ok := ankiFieldNames[strings.ToLower(strings.TrimSpace(name))]; ok {
+            if _, seen := idx[field]; !seen {
+                idx[field] = i
+            }
+        }
+    }
+    if _, ok := idx["japanese"]; ok {
+        return idx
+    }
+
+    switch {
+    case count >= 3:
+        return map[string]int{"japanese": 0, "romaji": 1, "english": 2}
+    case count == 2:
+        return map[string]int{"japanese": 0, "english": 1}
+    }
+    return map[string]int{"japanese": 0}
+}
+
+// maxCollectionSize caps the decompressed collection of a package, so a
+// small upload cannot expand into a file filling the temp disk.
+var maxCollectionSize int64 = 256 << 20
+
+// furigana matches Anki's base[reading] ruby notation, where the base
+// starts after a space or at the start of the field.
+var furigana = regexp.MustCompile(`\s?([^\s>\[\]]+?)\[([^\]]+)\]`)
+
+// ankiReading returns the kana of a reading field, keeping the reading of
+// furigana such as 食[た]べる as Anki's kana: filter does.
+func ankiReading(field string) string {
+    return strings.Join(strings.Fields(furigana.ReplaceAllString(field, "$2")), "")
+}
+
+var htmlTag = regexp.MustCompile(`(?i)<br\s*/?>|<[^>]*>`)
+
+// ankiText turns an HTML note field into plain text.
+func ankiText(field string) string {
+    text := htmlTag.ReplaceAllStringFunc(field, func(tag string) string {
+        if strings.HasPrefix(strings.ToLower(tag), "<br") {
+            return " "
+        }
+        return ""
+    })
+    text = strings.ReplaceAll(html.UnescapeString(text), "\u00a0", " ")
+    return strings.TrimSpace(text)
+}
+
+func ankiRow(line int, fields []string, idx map[string]int, isHTML bool) Row {
+    get := func(name string) string {
+        i, ok := idx[name]
+        if !ok || i >= len(fields) {
+            return ""
+        }
+        if isHTML {
+            return ankiText(fields[i])
+        }
+        return strings.TrimSpace(fields[i])
+    }
+
+    row := Row{Line: line, Word: models.Word{Japanese: get("japanese"), Romaji: get("romaji"), English: get("english")}}
+    row.setParts(get("parts"))
+    if reading := ankiReading(get("reading")); reading != "" {
+        if row.Word.Parts == nil {
+            row.Word.Parts = &models.Parts{}
+        }
+        if row.Word.Parts.Reading == "" {
+            row.Word.Parts.Reading = reading
+        }
+    }
+    return row
+}
+
+var ankiSeparators = map[string]rune{
+    "tab":       '\t',
+    "comma":     ',',
+    "semicolon": ';',
+    "pipe":      '|',
+    "space":     ' ',
+    "colon":     ':',
+}
+
+// decodeAnkiText reads the "Notes in Plain Text" export. Leading lines such
+// as "#separator:tab" and "#columns:Front\tBack" describe the file.
+func decodeAnkiText(r io.Reader) ([]Row, error) {
+    br := bufio.NewReader(r)
+    sep, isHTML := '\t', true
+    var columns []string
+    headerLines := 0
+    for {
+        peek, err := br.Peek(1)
+        if err != nil || peek[0] != '#' {
+            break
+        }
+        line, err := br.ReadString('\n')
+        if err != nil && err != io.EOF {
+            return nil, err
+        }
+        headerLines++
+
+        key, value, _ := strings.Cut(strings.TrimRight(strings.TrimPrefix(line, "#"), "\r\n"), ":")
+        switch strings.ToLower(key) {
+        case "separator":
+            if s, ok := ankiSeparators[strings.ToLower(value)]; ok {
+                sep = s
+            } else if len([]rune(value)) == 1 {
+                sep = []rune(value)[0]
+            } else {
+                return nil, fmt.Errorf("line %d: unknown separator %q", headerLines, value)
+            }
+        case "html":
+            isHTML = value == "true"
+        case "columns":
+            columns = strings.Split(value, string(sep))
+        }
+    }
+
+    reader := csv.NewReader(br)
+    reader.Comma = sep
+    reader.FieldsPerRecord = -1
+    reader.LazyQuotes = true
+
+    var rows []Row
+    for {
+        record, err := reader.Read()
+        if err == io.EOF {
+            return rows, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+
+        line, _ := reader.FieldPos(0)
+        idx := ankiFields(columns, len(record))
+        rows = append(rows, ankiRow(headerLines+line, record, idx, isHTML))
+    }
+}
+
+type ankiTextEncoder struct {
+    w *csv.Writer
+}
+
+func newAnkiTextEncoder(w io.Writer) (*ankiTextEncoder, error) {
+    if _, err := io.WriteString(w, "#separator:tab\n#html:false\n#columns:Japanese\tRomaji\tEnglish\tParts\n"); err != nil {
+        return nil, err
+    }
+    cw := csv.NewWriter(w)
+    cw.Comma = '\t'
+    return &ankiTextEncoder{w: cw}, nil
+}
+
+func (e *ankiTextEncoder) Encode(word models.Word) error {
+    parts, err := partsText(word.Parts)
+    if err != nil {
+        return err
+    }
+    return e.w.Write([]string{word.Japanese, word.Romaji, word.English, parts})
+}
+
+func (e *ankiTextEncoder) Close() error {
+    e.w.Flush()
+    return e.w.Error()
+}
+
+// decodeAnkiPackage reads the notes of an .apkg file, a zip archive holding
+// the deck's SQLite collection. Field names come from the note types.
+func decodeAnkiPackage(r io.ReaderAt, size int64) ([]Row, error) {
+    archive, err := zip.NewReader(r, size)
+    if err != nil {
+        return nil, fmt.Errorf("not an Anki package: %w", err)
+    }
+
+    files := map[string]*zip.File{}
+    for _, f := range archive.File {
+        files[f.Name] = f
+    }
+    // Current Anki writes its collection as collection.anki21b, next to a
+    // collection.anki2 stub whose only note asks to update Anki
+    collection := files["collection.anki21"]
+    if collection == nil && files["collection.anki21b"] == nil {
+        collection = files["collection.anki2"]
+    }
+    if collection == nil {
+        return nil, fmt.Errorf("Anki package has no legacy collection; export it with \"Support older Anki versions\" enabled")
+    }
+
+    dir, err := os.MkdirTemp("", "apkg")
+    if err != nil {
+        return nil, err
+    }
+    defer os.RemoveAll(dir)
+
+    path := filepath.Join(dir, "collection.db")
+    if err := extract(collection, path); err != nil {
+        return nil, err
+    }
+
+    db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+    if err != nil {
+        return nil, err
+    }
+    defer db.Close()
+
+    var modelsJSON string
+    if err := db.QueryRow("SELECT models FROM col").Scan(&modelsJSON); err != nil {
+        return nil, fmt.Errorf("read Anki note types: %w", err)
+    }
+    var noteTypes map[string]struct {
+        Flds []struct {
+            Name string `json:"name"`
+            Ord  int    `json:"ord"`
+        } `json:"flds"`
+    }
+    if err := json.Unmarshal([]byte(modelsJSON), &noteTypes); err != nil {
+        return nil, fmt.Errorf("read Anki note types: %w", err)
+    }
+
+    rows, err := db.Query("SELECT mid, flds FROM notes ORDER BY id")
+    if err != nil {
+        return nil, fmt.Errorf("read Anki notes: %w", err)
+    }
+    defer rows.Close()
+
+    var result []Row
+    for rows.Next() {
+        var mid int64
+        var flds string
+        if err := rows.Scan(&mid, &flds); err != nil {
+            return nil, err
+        }
+
+        fields := strings.Split(flds, "\x1f")
+        names := make([]string, len(fields))
+        for _, f := range noteTypes[strconv.FormatInt(mid, 10)].Flds {
+            if f.Ord < len(names) {
+                names[f.Ord] = f.Name
+            }
+        }
+        result = append(result, ankiRow(len(result)+1, fields, ankiFields(names, len(fields)), true))
+    }
+    return result, rows.Err()
+}
+
+// extract writes f to path, refusing to write more than maxCollectionSize
+// whatever size the archive claims.
+func extract(f *zip.File, path string) error {
+    tooLarge := fmt.Errorf("Anki collection is larger than %d MB uncompressed", maxCollectionSize>>20)
+    if f.UncompressedSize64 > uint64(maxCollectionSize) {
+        return tooLarge
+    }
+
+    src, err := f.Open()
+    if err != nil {
+        return err
+    }
+    defer src.Close()
+
+    dst, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    n, err := io.Copy(dst, io.LimitReader(src, maxCollectionSize+1))
+    if err == nil && n > maxCollectionSize {
+        err = tooLarge
+    }
+    if err != nil {
+        dst.Close()
+        return err
+    }
+    return dst.Close()
+}
+
+// ankiSchema is the legacy (schema 11) collection layout that every Anki
+// version can import.
+const ankiSchema = `
+CREATE TABLE col (
+    id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+    ver integer not null, dty integer not null, usn integer not null, ls integer not null,
+    conf text not null, models text not null, decks text not null, dconf text not null, tags text not null
+);
+CREATE TABLE notes (
+    id integer primary key, guid text not null, mid integer not null, mod integer not null,
+    usn integer not null, tags text not null, flds text not null, sfld integer not null,
+    csum integer not null, flags integer not null, data text not null
+);
+CREATE TABLE cards (
+    id integer primary key, nid integer not null, did integer not null, ord integer not null,
+    mod integer not null, usn integer not null, type integer not null, queue integer not null,
+    due integer not null, ivl integer not null, factor integer not null, reps integer not null,
+    lapses integer not null, left integer not null, odue integer not null, odid integer not null,
+    flags integer not null, data text not null
+);
+CREATE TABLE revlog (
+    id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+    ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null,
+    type integer not null
+);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+const ankiDeckName = "Lang Portal"
+
+// ankiPackageEncoder builds the collection in a temporary SQLite file and
+// zips it into the output on Close, since a package cannot be streamed.
+type ankiPackageEncoder struct {
+    w      io.Writer
+    dir    string
+    db     *sql.DB
+    tx     *sql.Tx
+    now    time.Time
+    noteID int64
+    deckID int64
+    mid    int64
+    count  int
+}
+
+func newAnkiPackageEncoder(w io.Writer) (*ankiPackageEncoder, error) {
+    dir, err := os.MkdirTemp("", "apkg")
+    if err != nil {
+        return nil, err
+    }
+
+    e := &ankiPackageEncoder{w: w, dir: dir, now: time.Now()}
+    e.noteID = e.now.UnixNano() / int64(time.Millisecond)
+    e.deckID = e.noteID
+    e.mid = e.noteID + 1
+
+    if err := e.init(); err != nil {
+        e.cleanup()
+        return nil, err
+    }
+    return e, nil
+}
+
+func (e *ankiPackageEncoder) init() error {
+    var err error
+    e.db, err = sql.Open("sqlite3", filepath.Join(e.dir, "collection.anki2"))
+    if err != nil {
+        return err
+    }
+    if _, err := e.db.Exec(ankiSchema); err != nil {
+        return err
+    }
+    e.tx, err = e.db.Begin()
+    return err
+}
+
+func (e *ankiPackageEncoder) Encode(word models.Word) error {
+    parts, err := partsText(word.Parts)
+    if err != nil {
+        return err
+    }
+    fields := []string{
+        html.EscapeString(word.Japanese),
+        html.EscapeString(word.Romaji),
+        html.EscapeString(word.English),
+        html.EscapeString(parts),
+    }
+    sum := sha1.Sum([]byte(word.Japanese))
+    csum, _ := strconv.ParseInt(hex.EncodeToString(sum[:4]), 16, 64)
+
+    // A guid derived from the word id lets Anki update the note when the
+    // same word is exported again instead of adding a duplicate
+    guid := "langportal-" + strconv.FormatInt(word.ID, 10)
+    if word.ID == 0 {
+        guid = "langportal-n" + strconv.Itoa(e.count)
+    }
+
+    e.count++
+    id := e.noteID + int64(e.count)
+    mod := e.now.Unix()
+    _, err = e.tx.Exec(`INSERT INTO notes (id, guid, mid, mod, usn, tags, flds, sfld, csum, flags, data)
+                         VALUES (?, ?, ?, ?, -1, '', ?, ?, ?, 0, '')`,
+        id, guid, e.mid, mod, strings.Join(fields, "\x1f"), word.Japanese, csum)
+    if err != nil {
+        return err
+    }
+    _, err = e.tx.Exec(`INSERT INTO cards (id, nid, did, ord, mod, usn, type, queue, due, ivl, factor, reps, lapses, left, odue, odid, flags, data)
+                        VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+        id, id, e.deckID, mod, e.count)
+    return err
+}
+
+func (e *ankiPackageEncoder) Close() error {
+    defer e.cleanup()
+
+    if err := e.writeCollection(); err != nil {
+        return err
+    }
+    if err := e.tx.Commit(); err != nil {
+        return err
+    }
+    if err := e.db.Close(); err != nil {
+        return err
+    }
+
+    zw := zip.NewWriter(e.w)
+    if err := addFile(zw, "collection.anki2", filepath.Join(e.dir, "collection.anki2"), e.now); err != nil {
+        return err
+    }
+    media, err := zw.CreateHeader(&zip.FileHeader{Name: "media", Method: zip.Deflate, Modified: e.now})
+    if err != nil {
+        return err
+    }
+    if _, err := io.WriteString(media, "{}"); err != nil {
+        return err
+    }
+    return zw.Close()
+}
+
+func (e *ankiPackageEncoder) cleanup() {
+    if e.db != nil {
+        e.db.Close()
+    }
+    os.RemoveAll(e.dir)
+}
+
+func (e *ankiPackageEncoder) writeCollection() error {
+    mod := e.now.Unix()
+    mid := strconv.FormatInt(e.mid, 10)
+
+    field := func(name string, ord int) map[string]interface{} {
+        return map[string]interface{}{
+            "name": name, "ord": ord, "sticky": false, "rtl": false,
+            "font": "Arial", "size": 20, "media": []string{},
+        }
+    }
+    noteTypes := map[string]interface{}{
+        mid: map[string]interface{}{
+            "id": e.mid, "name": "Lang Portal Word", "type": 0, "mod": mod, "usn": -1,
+            "sortf": 0, "did": e.deckID, "tags": []string{}, "vers": []string{},
+            "flds": []interface{}{field("Japanese", 0), field("Romaji", 1), field("English", 2), field("Parts", 3)},
+            "tmpls": []interface{}{map[string]interface{}{
+                "name": "Card 1", "ord": 0, "did": nil, "bqfmt": "", "bafmt": "",
+                "qfmt": "{{Japanese}}",
+                "afmt": "{{FrontSide}}\n\n<hr id=answer>\n\n{{Romaji}}<br>\n{{English}}",
+            }},
+            "css":       ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n}\n",
+            "latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+            "latexPost": "\\end{document}",
+            "req":       []interface{}{[]interface{}{0, "any", []int{0}}},
+        },
+    }
+    deck := func(id int64, name string) map[string]interface{} {
+        return map[string]interface{}{
+            "id": id, "name": name, "desc": "", "mod": mod, "usn": -1, "dyn": 0, "conf": 1,
+            "collapsed": false, "browserCollapsed": false, "extendNew": 10, "extendRev": 50,
+            "newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+        }
+    }
+    decks := map[string]interface{}{
+        "1": deck(1, "Default"),
+        strconv.FormatInt(e.deckID, 10): deck(e.deckID, ankiDeckName),
+    }
+    deckConf := map[string]interface{}{
+        "1": map[string]interface{}{
+            "id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true,
+            "timer": 0, "replayq": true, "dyn": false,
+            "new":   map[string]interface{}{"bury": true, "delays": []int{1, 10}, "initialFactor": 2500, "ints": []int{1, 4, 7}, "order": 1, "perDay": 20, "separate": true},
+            "rev":   map[string]interface{}{"bury": true, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "minSpace": 1, "perDay": 200},
+            "lapse": map[string]interface{}{"delays": []int{10}, "leechAction": 0, "leechFails": 8, "minInt": 1, "mult": 0},
+        },
+    }
+    conf := map[string]interface{}{
+        "nextPos": e.count + 1, "estTimes": true, "activeDecks": []int64{e.deckID}, "sortType": "noteFld",
+        "timeLim": 0, "sortBackwards": false, "addToCur": true, "curDeck": e.deckID, "newBury": true,
+        "newSpread": 0, "dueCounts": true, "curModel": mid, "collapseTime": 1200,
+    }
+
+    encoded := make([]string, 0, 4)
+    for _, v := range []interface{}{conf, noteTypes, decks, deckConf} {
+        body, err := json.Marshal(v)
+        if err != nil {
+            return err
+        }
+        encoded = append(encoded, string(body))
+    }
+
+    y, m, d := e.now.Date()
+    crt := time.Date(y, m, d, 0, 0, 0, 0, e.now.Location()).Unix()
+    _, err := e.tx.Exec(`INSERT INTO col (id, crt, mod, scm, ver, dty, usn, ls, conf, models, decks, dconf, tags)
+                         VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+        crt, e.noteID, e.noteID, encoded[0], encoded[1], encoded[2], encoded[3])
+    return err
+}
+
+func addFile(zw *zip.Writer, name, path string, modified time.Time) error {
+    src, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer src.Close()
+
+    dst, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+    if err != nil {
+        return err
+    }
+    _, err = io.Copy(dst, src)
+    return err
+}
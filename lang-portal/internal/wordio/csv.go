@@ -0,0 +1,90 @@
+package wordio
+
+import (
+    "encoding/csv"
+    "fmt"
+    "io"
+    "strings"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+var csvHeader = []string{"japanese", "romaji", "english", "parts"}
+
+// decodeCSV reads a CSV file whose header names the columns. japanese,
+// romaji and english are required; parts, if present, holds JSON.
+func decodeCSV(r io.Reader) ([]Row, error) {
+    reader := csv.NewReader(r)
+    reader.FieldsPerRecord = -1
+    reader.TrimLeadingSpace = true
+
+    header, err := reader.Read()
+    if err == io.EOF {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    columns := map[string]int{}
+    for i, name := range header {
+        columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+    }
+    for _, name := range csvHeader[:3] {
+        if _, ok := columns[name]; !ok {
+            return nil, fmt.Errorf("csv header is missing the %s column", name)
+        }
+    }
+
+    field := func(record []string, name string) string {
+        i, ok := columns[name]
+        if !ok || i >= len(record) {
+            return ""
+        }
+        return strings.TrimSpace(record[i])
+    }
+
+    var rows []Row
+    for {
+        record, err := reader.Read()
+        if err == io.EOF {
+            return rows, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+
+        line, _ := reader.FieldPos(0)
+        row := Row{Line: line, Word: models.Word{
+            Japanese: field(record, "japanese"),
+            Romaji:   field(record, "romaji"),
+            English:  field(record, "english"),
+        }}
+        row.setParts(field(record, "parts"))
+        rows = append(rows, row)
+    }
+}
+
+type csvEncoder struct {
+    w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) (*csvEncoder, error) {
+    cw := csv.NewWriter(w)
+    if err := cw.Write(csvHeader); err != nil {
+        return nil, err
+    }
+    return &csvEncoder{w: cw}, nil
+}
+
+func (e *csvEncoder) Encode(word models.Word) error {
+    parts, err := partsText(word.Parts)
+    if err != nil {
+        return err
+    }
+    return e.w.Write([]string{word.Japanese, word.Romaji, word.English, parts})
+}
+
+func (e *csvEncoder) Close() error {
+    e.w.Flush()
+    return e.w.Error()
+}
@@ -0,0 +1,98 @@
+package wordio
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+// decodeJSON reads a JSON array of words as returned by the API. Line is
+// the 1-based position of the word in the array. A word that does not
+// decode keeps the fields that do, and the others are reported in Invalid.
+func decodeJSON(r io.Reader) ([]Row, error) {
+    dec := json.NewDecoder(r)
+    if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+        return nil, fmt.Errorf("json import must be an array of words")
+    }
+
+    var rows []Row
+    for dec.More() {
+        var raw json.RawMessage
+        if err := dec.Decode(&raw); err != nil {
+            return nil, fmt.Errorf("word %d: %w", len(rows)+1, err)
+        }
+        row := Row{Line: len(rows) + 1}
+        row.setWord(raw)
+        rows = append(rows, row)
+    }
+    if _, err := dec.Token(); err != nil {
+        return nil, err
+    }
+    return rows, nil
+}
+
+// setWord decodes a JSON word into the row. When the word as a whole does
+// not decode, each field is decoded on its own to find the faulty ones.
+func (r *Row) setWord(raw json.RawMessage) {
+    defer func() {
+        r.Word.ID, r.Word.CreatedAt, r.Word.UpdatedAt = 0, "", ""
+    }()
+    if err := json.Unmarshal(raw, &r.Word); err == nil {
+        return
+    }
+
+    var fields map[string]json.RawMessage
+    if err := json.Unmarshal(raw, &fields); err != nil {
+        r.Word = models.Word{}
+        r.Invalid = map[string]string{"word": "must be a JSON object"}
+        return
+    }
+    r.Word = models.Word{}
+    r.Invalid = map[string]string{}
+    for name, value := range fields {
+        field, err := json.Marshal(map[string]json.RawMessage{name: value})
+        if err != nil {
+            r.Invalid[name] = err.Error()
+            continue
+        }
+        if err := json.Unmarshal(field, &r.Word); err != nil {
+            r.Invalid[name] = err.Error()
+        }
+    }
+}
+
+type jsonEncoder struct {
+    w     io.Writer
+    count int
+}
+
+func newJSONEncoder(w io.Writer) *jsonEncoder {
+    return &jsonEncoder{w: w}
+}
+
+func (e *jsonEncoder) Encode(word models.Word) error {
+    sep := ",\n"
+    if e.count == 0 {
+        sep = "[\n"
+    }
+    body, err := json.Marshal(word)
+    if err != nil {
+        return err
+    }
+    if _, err := io.WriteString(e.w, sep); err != nil {
+        return err
+    }
+    e.count++
+    _, err = e.w.Write(body)
+    return err
+}
+
+func (e *jsonEncoder) Close() error {
+    end := "\n]\n"
+    if e.count == 0 {
+        end = "[]\n"
+    }
+    _, err := io.WriteString(e.w, end)
+    return err
+}
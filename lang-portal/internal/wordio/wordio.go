@@ -0,0 +1,127 @@
+// Package wordio reads and writes vocabulary files: CSV, JSON arrays, Anki
+// plain text notes and Anki .apkg packages.
+package wordio
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "path/filepath"
+    "strings"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+// Format is a supported vocabulary file format.
+type Format string
+
+const (
+    CSV      Format = "csv"
+    JSON     Format = "json"
+    AnkiText Format = "txt"
+    Anki     Format = "apkg"
+)
+
+// Formats lists every supported format.
+var Formats = []Format{CSV, JSON, AnkiText, Anki}
+
+// ParseFormat validates a format name such as "csv".
+func ParseFormat(name string) (Format, error) {
+    for _, f := range Formats {
+        if string(f) == strings.ToLower(name) {
+            return f, nil
+        }
+    }
+    return "", fmt.Errorf("unsupported format %q", name)
+}
+
+// FormatFromFilename picks the format from a file extension.
+func FormatFromFilename(name string) (Format, error) {
+    return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+// ContentType returns the MIME type used when serving f.
+func (f Format) ContentType() string {
+    switch f {
+    case CSV:
+        return "text/csv; charset=utf-8"
+    case JSON:
+        return "application/json; charset=utf-8"
+    case AnkiText:
+        return "text/plain; charset=utf-8"
+    default:
+        return "application/octet-stream"
+    }
+}
+
+// Row is a decoded word together with the record it came from, so
+// validation problems can point at the offending line or note.
+type Row struct {
+    Line int
+    Word models.Word
+    // Invalid holds problems found while decoding, keyed by field
+    Invalid map[string]string
+}
+
+// setParts decodes the parts JSON of a text column into the row.
+func (r *Row) setParts(text string) {
+    if text == "" {
+        return
+    }
+    var parts models.Parts
+    if err := json.Unmarshal([]byte(text), &parts); err != nil {
+        r.Invalid = map[string]string{"parts": err.Error()}
+        return
+    }
+    r.Word.Parts = &parts
+}
+
+// partsText encodes parts for a text column, empty when there are none.
+func partsText(parts *models.Parts) (string, error) {
+    if parts == nil {
+        return "", nil
+    }
+    data, err := json.Marshal(parts)
+    return string(data), err
+}
+
+// Decode reads every word in r. Anki packages are zip archives and need
+// random access, so r must also implement io.ReaderAt for them.
+func Decode(f Format, r io.Reader, size int64) ([]Row, error) {
+    switch f {
+    case CSV:
+        return decodeCSV(r)
+    case JSON:
+        return decodeJSON(r)
+    case AnkiText:
+        return decodeAnkiText(r)
+    case Anki:
+        ra, ok := r.(io.ReaderAt)
+        if !ok {
+            return nil, fmt.Errorf("reading an Anki package needs random access")
+        }
+        return decodeAnkiPackage(ra, size)
+    }
+    return nil, fmt.Errorf("unsupported format %q", f)
+}
+
+// Encoder writes words one at a time so large vocabularies can be streamed.
+// Close must be called to finish the file.
+type Encoder interface {
+    Encode(w models.Word) error
+    Close() error
+}
+
+// NewEncoder returns an encoder writing f to w.
+func NewEncoder(f Format, w io.Writer) (Encoder, error) {
+    switch f {
+    case CSV:
+        return newCSVEncoder(w)
+    case JSON:
+        return newJSONEncoder(w), nil
+    case AnkiText:
+        return newAnkiTextEncoder(w)
+    case Anki:
+        return newAnkiPackageEncoder(w)
+    }
+    return nil, fmt.Errorf("unsupported format %q", f)
+}
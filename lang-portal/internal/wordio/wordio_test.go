@@ -0,0 +1,180 @@
+package wordio
+
+import (
+    "archive/zip"
+    "bytes"
+    "io"
+    "strings"
+    "testing"
+    "github.com/stretchr/testify/assert"
+    "github.com/stretchr/testify/require"
+    "github.com/karl247ai/lang-portal/internal/models"
+)
+
+var testWords = []models.Word{
+    {ID: 1, Japanese: "猫", Romaji: "neko", English: "cat", Parts: &models.Parts{PartOfSpeech: "noun", Components: []models.PartComponent{{Kanji: "猫", Reading: "ねこ"}}}},
+    {ID: 2, Japanese: "食べる", Romaji: "taberu", English: "to eat, \"consume\""},
+    {ID: 3, Japanese: "<b>&</b>", Romaji: "tab\there", English: "line\nbreak"},
+}
+
+func encode(t *testing.T, f Format, words []models.Word) []byte {
+    var buf bytes.Buffer
+    enc, err := NewEncoder(f, &buf)
+    require.NoError(t, err)
+    for _, w := range words {
+        require.NoError(t, enc.Encode(w))
+    }
+    require.NoError(t, enc.Close())
+    return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+    for _, f := range Formats {
+        t.Run(string(f), func(t *testing.T) {
+            data := encode(t, f, testWords)
+
+            rows, err := Decode(f, bytes.NewReader(data), int64(len(data)))
+            require.NoError(t, err)
+            require.Len(t, rows, len(testWords))
+            for i, row := range rows {
+                want := testWords[i]
+                assert.Equal(t, want.Japanese, row.Word.Japanese)
+                assert.Equal(t, want.Romaji, row.Word.Romaji)
+                assert.Equal(t, want.English, row.Word.English)
+                assert.Equal(t, want.Parts, row.Word.Parts)
+                assert.Empty(t, row.Invalid)
+                assert.Zero(t, row.Word.ID)
+            }
+        })
+    }
+}
+
+func TestRoundTrip_Empty(t *testing.T) {
+    for _, f := range Formats {
+        data := encode(t, f, nil)
+        rows, err := Decode(f, bytes.NewReader(data), int64(len(data)))
+        assert.NoError(t, err, f)
+        assert.Empty(t, rows, f)
+    }
+}
+
+func TestDecodeCSV(t *testing.T) {
+    rows, err := Decode(CSV, strings.NewReader("\ufeffEnglish,Japanese,Romaji\ncat,猫,neko\n\ndog,犬,inu\n"), 0)
+    require.NoError(t, err)
+    require.Len(t, rows, 2)
+    assert.Equal(t, Row{Line: 2, Word: models.Word{Japanese: "猫", Romaji: "neko", English: "cat"}}, rows[0])
+    assert.Equal(t, 4, rows[1].Line)
+
+    _, err = Decode(CSV, strings.NewReader("japanese,english\n猫,cat\n"), 0)
+    assert.EqualError(t, err, "csv header is missing the romaji column")
+}
+
+func TestDecodeAnkiText(t *testing.T) {
+    input := "#separator:Pipe\n#html:true\n#columns:Front|Back\n<b>猫</b>|cat&nbsp;<br>(animal)\n犬|dog\n"
+    rows, err := Decode(AnkiText, strings.NewReader(input), 0)
+    require.NoError(t, err)
+    require.Len(t, rows, 2)
+    assert.Equal(t, Row{Line: 4, Word: models.Word{Japanese: "猫", English: "cat  (animal)"}}, rows[0])
+    assert.Equal(t, 5, rows[1].Line)
+
+    // Without a header, three columns are japanese, romaji and english
+    rows, err = Decode(AnkiText, strings.NewReader("猫\tneko\tcat\n"), 0)
+    require.NoError(t, err)
+    assert.Equal(t, models.Word{Japanese: "猫", Romaji: "neko", English: "cat"}, rows[0].Word)
+}
+
+func TestDecodeAnkiText_Reading(t *testing.T) {
+    input := "#separator:tab\n#html:true\n#columns:Expression\tReading\tMeaning\n" +
+        "食べる\t食[た]べる\tto eat\n" +
+        "今日は\t 今日[きょう]は<br>\thello\n" +
+        "ねこ\tねこ\tcat\n"
+    rows, err := Decode(AnkiText, strings.NewReader(input), 0)
+    require.NoError(t, err)
+    require.Len(t, rows, 3)
+
+    // Romaji is left for validator.FillRomaji to transliterate
+    assert.Equal(t, models.Word{Japanese: "食べる", English: "to eat", Parts: &models.Parts{Reading: "たべる"}}, rows[0].Word)
+    assert.Equal(t, "きょうは", rows[1].Word.Parts.Reading)
+    assert.Equal(t, "ねこ", rows[2].Word.Parts.Reading)
+    assert.Empty(t, rows[2].Invalid)
+
+    rows, err = Decode(AnkiText, strings.NewReader("#columns:Word\tKana\tMeaning\n猫\tねこ\tcat\n"), 0)
+    require.NoError(t, err)
+    assert.Equal(t, models.Word{Japanese: "猫", English: "cat", Parts: &models.Parts{Reading: "ねこ"}}, rows[0].Word)
+}
+
+func TestDecodeJSON(t *testing.T) {
+    _, err := Decode(JSON, strings.NewReader(`{"japanese": "猫"}`), 0)
+    assert.Error(t, err)
+
+    rows, err := Decode(JSON, strings.NewReader(`[{"id": 7, "japanese": "猫", "romaji": "neko", "english": "cat"}]`), 0)
+    require.NoError(t, err)
+    assert.Equal(t, Row{Line: 1, Word: models.Word{Japanese: "猫", Romaji: "neko", English: "cat"}}, rows[0])
+
+    // Words that do not decode are reported per field, the others still load
+    rows, err = Decode(JSON, strings.NewReader(`[
+        {"japanese": "犬", "romaji": 5, "english": "dog"},
+        {"japanese": "猫", "english": "cat", "parts": {"meaning": "animal"}},
+        "猫",
+        {"japanese": "鳥", "romaji": "tori", "english": "bird"}
+    ]`), 0)
+    require.NoError(t, err)
+    require.Len(t, rows, 4)
+    assert.Equal(t, models.Word{Japanese: "犬", English: "dog"}, rows[0].Word)
+    assert.Contains(t, rows[0].Invalid, "romaji")
+    assert.Equal(t, "cat", rows[1].Word.English)
+    assert.Contains(t, rows[1].Invalid, "parts")
+    assert.Equal(t, map[string]string{"word": "must be a JSON object"}, rows[2].Invalid)
+    assert.Equal(t, Row{Line: 4, Word: models.Word{Japanese: "鳥", Romaji: "tori", English: "bird"}}, rows[3])
+}
+
+func TestDecodeAnkiPackage_NotZip(t *testing.T) {
+    _, err := Decode(Anki, strings.NewReader("not a zip"), 9)
+    assert.Error(t, err)
+}
+
+func TestDecodeAnkiPackage_TooLarge(t *testing.T) {
+    pkg := encode(t, Anki, testWords)
+    defer func(n int64) { maxCollectionSize = n }(maxCollectionSize)
+    maxCollectionSize = 1 << 10
+
+    _, err := Decode(Anki, bytes.NewReader(pkg), int64(len(pkg)))
+    assert.ErrorContains(t, err, "Anki collection is larger than")
+}
+
+func TestDecodeAnkiPackage_NewFormat(t *testing.T) {
+    pkg := encode(t, Anki, testWords)
+    archive, err := zip.NewReader(bytes.NewReader(pkg), int64(len(pkg)))
+    require.NoError(t, err)
+
+    // A current export: the legacy collection is only a stub next to
+    // collection.anki21b
+    var buf bytes.Buffer
+    zw := zip.NewWriter(&buf)
+    for _, f := range archive.File {
+        src, err := f.Open()
+        require.NoError(t, err)
+        dst, err := zw.Create(f.Name)
+        require.NoError(t, err)
+        _, err = io.Copy(dst, src)
+        require.NoError(t, err)
+        src.Close()
+    }
+    dst, err := zw.Create("collection.anki21b")
+    require.NoError(t, err)
+    _, err = dst.Write([]byte("zstd compressed collection"))
+    require.NoError(t, err)
+    require.NoError(t, zw.Close())
+
+    _, err = Decode(Anki, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+    assert.ErrorContains(t, err, "Support older Anki versions")
+}
+
+func TestFormatFromFilename(t *testing.T) {
+    f, err := FormatFromFilename("deck.APKG")
+    assert.NoError(t, err)
+    assert.Equal(t, Anki, f)
+
+    _, err = FormatFromFilename("words.xlsx")
+    assert.Error(t, err)
+}
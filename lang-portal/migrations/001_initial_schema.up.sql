@@ -8,5 +8,5 @@ CREATE TABLE IF NOT EXISTS words (
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE INDEX idx_words_romaji ON words(romaji);
-CREATE INDEX idx_words_english ON words(english);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_words_romaji ON words(romaji);
+CREATE INDEX IF NOT EXISTS idx_words_english ON words(english);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS words_groups;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS word_review_items;
+DROP TABLE IF EXISTS study_sessions;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_study_sessions_study_activity_id;
+DROP TABLE IF EXISTS study_activities;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS study_activities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    thumbnail_url TEXT,
+    description TEXT,
+    launch_url TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_study_sessions_study_activity_id ON study_sessions(study_activity_id);
+
+INSERT OR IGNORE INTO study_activities (name, thumbnail_url, description, launch_url) VALUES
+    ('Vocabulary Quiz', '/thumbnails/vocabulary-quiz.png', 'Practice your vocabulary with flashcards', 'http://localhost:8081'),
+    ('Sentence Constructor', '/thumbnails/sentence-constructor.png', 'Build Japanese sentences from English prompts', 'http://localhost:8082');
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_word_review_items_created_at ON word_review_items(created_at);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS words_fts_delete;
+DROP TRIGGER IF EXISTS words_fts_update;
+DROP TRIGGER IF EXISTS words_fts_insert;
+DROP TABLE IF EXISTS words_fts;
@@ -0,0 +1,39 @@
+-- Full-text index over words. parts is indexed as the text values found in
+-- its JSON so keys such as "kanji" or "romaji" do not match every word.
+-- Requires SQLite built with FTS5 (go build -tags sqlite_fts5).
+CREATE VIRTUAL TABLE IF NOT EXISTS words_fts USING fts5(
+    japanese,
+    romaji,
+    english,
+    parts,
+    tokenize = 'unicode61 remove_diacritics 2',
+    prefix = '2 3'
+);
+
+INSERT INTO words_fts (rowid, japanese, romaji, english, parts)
+SELECT id, japanese, romaji, english,
+       CASE WHEN json_valid(CAST(parts AS TEXT)) THEN
+           (SELECT group_concat(value, ' ') FROM json_tree(CAST(parts AS TEXT)) WHERE type = 'text')
+       END
+FROM words;
+
+CREATE TRIGGER IF NOT EXISTS words_fts_insert AFTER INSERT ON words BEGIN
+    INSERT INTO words_fts (rowid, japanese, romaji, english, parts)
+    VALUES (new.id, new.japanese, new.romaji, new.english,
+            CASE WHEN json_valid(CAST(new.parts AS TEXT)) THEN
+                (SELECT group_concat(value, ' ') FROM json_tree(CAST(new.parts AS TEXT)) WHERE type = 'text')
+            END);
+END;
+
+CREATE TRIGGER IF NOT EXISTS words_fts_update AFTER UPDATE OF japanese, romaji, english, parts ON words BEGIN
+    DELETE FROM words_fts WHERE rowid = old.id;
+    INSERT INTO words_fts (rowid, japanese, romaji, english, parts)
+    VALUES (new.id, new.japanese, new.romaji, new.english,
+            CASE WHEN json_valid(CAST(new.parts AS TEXT)) THEN
+                (SELECT group_concat(value, ' ') FROM json_tree(CAST(new.parts AS TEXT)) WHERE type = 'text')
+            END);
+END;
+
+CREATE TRIGGER IF NOT EXISTS words_fts_delete AFTER DELETE ON words BEGIN
+    DELETE FROM words_fts WHERE rowid = old.id;
+END;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_words_jlpt_level;
+DROP INDEX IF EXISTS idx_words_part_of_speech;
+
+-- Parts holding nothing but components go back to the legacy array form
+UPDATE words SET parts = json_extract(parts, '$.components')
+WHERE json_type(parts, '$.components') = 'array'
+  AND (SELECT COUNT(*) FROM json_each(words.parts)) = 1;
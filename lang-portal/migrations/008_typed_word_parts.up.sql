@@ -0,0 +1,22 @@
+-- parts becomes a JSON object with the fields of models.Parts, stored as
+-- text. Legacy arrays of kanji components move under "components", JLPT
+-- levels written "N5" become 5 and parts of speech are lower-cased. Values
+-- that are not a JSON object or array cannot be typed and are cleared.
+UPDATE words SET parts = NULL
+WHERE parts IS NOT NULL
+  AND COALESCE(CASE WHEN json_valid(CAST(parts AS TEXT)) THEN json_type(CAST(parts AS TEXT)) END, '') NOT IN ('object', 'array');
+
+UPDATE words SET parts = json_object('components', json(CAST(parts AS TEXT)))
+WHERE json_type(CAST(parts AS TEXT)) = 'array';
+
+UPDATE words SET parts = json(CAST(parts AS TEXT))
+WHERE parts IS NOT NULL;
+
+UPDATE words SET parts = json_set(parts, '$.jlpt_level', CAST(ltrim(upper(json_extract(parts, '$.jlpt_level')), 'N') AS INTEGER))
+WHERE json_type(parts, '$.jlpt_level') = 'text';
+
+UPDATE words SET parts = json_set(parts, '$.part_of_speech', lower(json_extract(parts, '$.part_of_speech')))
+WHERE json_type(parts, '$.part_of_speech') = 'text';
+
+CREATE INDEX IF NOT EXISTS idx_words_part_of_speech ON words(json_extract(parts, '$.part_of_speech'));
+CREATE INDEX IF NOT EXISTS idx_words_jlpt_level ON words(json_extract(parts, '$.jlpt_level'));
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS words_norm_reset;
+DROP INDEX IF EXISTS idx_words_norm;
+ALTER TABLE words DROP COLUMN english_norm;
+ALTER TABLE words DROP COLUMN japanese_norm;
@@ -0,0 +1,15 @@
+-- Normalized japanese and english text used to detect duplicate words, see
+-- package textnorm. The normalization needs Unicode tables SQLite lacks, so
+-- the application fills the columns: NULL means not computed yet. Writes
+-- that change the text without recomputing the keys reset them to NULL.
+ALTER TABLE words ADD COLUMN japanese_norm TEXT;
+ALTER TABLE words ADD COLUMN english_norm TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_words_norm ON words(japanese_norm, english_norm);
+
+CREATE TRIGGER IF NOT EXISTS words_norm_reset AFTER UPDATE OF japanese, english ON words
+WHEN (new.japanese IS NOT old.japanese OR new.english IS NOT old.english)
+ AND new.japanese_norm IS old.japanese_norm AND new.english_norm IS old.english_norm
+BEGIN
+    UPDATE words SET japanese_norm = NULL, english_norm = NULL WHERE id = new.id;
+END;
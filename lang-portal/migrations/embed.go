@@ -0,0 +1,11 @@
+// Package migrations embeds the SQL schema migrations so the server and the
+// migrate command ship them inside the binary.
+//
+// Files are named NNN_description.up.sql with an optional matching
+// NNN_description.down.sql and are applied in version order.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
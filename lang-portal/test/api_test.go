@@ -11,7 +11,7 @@ import (
 )
 
 func TestHealthCheck(t *testing.T) {
-    router := setupTestRouter()
+    router := setupTestRouter(t)
     w := httptest.NewRecorder()
     req, _ := http.NewRequest("GET", "/health", nil)
     router.ServeHTTP(w, req)
@@ -58,7 +58,7 @@ func TestWordManagement(t *testing.T) {
         },
     }
 
-    router := setupTestRouter()
+    router := setupTestRouter(t)
 
     for _, tt := range tests {
         t.Run(tt.name, func(t *testing.T) {
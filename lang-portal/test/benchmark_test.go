@@ -1,5 +1,5 @@
 func BenchmarkWordOperations(b *testing.B) {
-    db := setupTestDB()
+    db := setupTestDB(b)
     defer db.Close()
 
     // Single word creation benchmark
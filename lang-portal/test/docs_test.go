@@ -38,7 +38,7 @@ func TestAPIDocumentation(t *testing.T) {
         },
     }
 
-    router := setupTestRouter()
+    router := setupTestRouter(t)
 
     for _, tt := range tests {
         t.Run(tt.name, func(t *testing.T) {
@@ -10,7 +10,7 @@ import (
     "github.com/karl247ai/lang-portal/internal/models"
     "github.com/karl247ai/lang-portal/internal/repository"
     "github.com/karl247ai/lang-portal/internal/api/handlers"
-    _ "github.com/mattn/go-sqlite3"
+    "github.com/karl247ai/lang-portal/internal/testdb"
     "testing"
     "time"
 )
@@ -22,38 +22,15 @@ type TestResponse struct {
     Error  string      `json:"error,omitempty"`
 }
 
-func setupTestDB() *sql.DB {
-    db, err := sql.Open("sqlite3", ":memory:")
-    if (err != nil) {
-        panic(err)
-    }
-
-    // Create test tables with additional indices
-    _, err = db.Exec(`
-        CREATE TABLE IF NOT EXISTS words (
-            id INTEGER PRIMARY KEY AUTOINCREMENT,
-            japanese TEXT NOT NULL,
-            romaji TEXT NOT NULL,
-            english TEXT NOT NULL,
-            created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-        );
-        
-        CREATE INDEX IF NOT EXISTS idx_words_romaji ON words(romaji);
-        CREATE INDEX IF NOT EXISTS idx_words_english ON words(english);
-    `)
-    if err != nil {
-        panic(err)
-    }
-
-    return db
+func setupTestDB(t testing.TB) *sql.DB {
+    return testdb.Open(t)
 }
 
-func setupTestRouter() *gin.Engine {
+func setupTestRouter(t testing.TB) *gin.Engine {
     gin.SetMode(gin.TestMode)
     r := gin.Default()
     
-    db := setupTestDB()
+    db := setupTestDB(t)
     wordRepo := repository.NewWordRepository(db)
     wordHandler := handlers.NewWordHandler(wordRepo)
     
@@ -66,6 +43,7 @@ func setupTestRouter() *gin.Engine {
     {
         api.GET("/words", wordHandler.GetWords)
         api.POST("/words", wordHandler.CreateWord)
+        api.GET("/words/:id", wordHandler.GetWord)
     }
     
     return r
@@ -37,7 +37,7 @@ func TestWordManagementFlow(t *testing.T) {
         },
     }
 
-    router := setupTestRouter()
+    router := setupTestRouter(t)
 
     for _, tt := range tests {
         t.Run(tt.name, func(t *testing.T) {
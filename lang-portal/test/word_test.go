@@ -14,9 +14,9 @@ func TestWordSuite(t *testing.T) {
     t.Parallel()
     
     // Common setup
-    db := setupTestDB()
+    db := setupTestDB(t)
     defer db.Close()
-    router := setupTestRouter()
+    router := setupTestRouter(t)
 
     // Run test groups in parallel
     t.Run("group=crud", func(t *testing.T) {
@@ -132,10 +132,10 @@ func TestWordManagement(t *testing.T) {
         },
     }
 
-    db := setupTestDB()
+    db := setupTestDB(t)
     defer db.Close()
     
-    router := setupTestRouter()
+    router := setupTestRouter(t)
 
     for _, tt := range tests {
         tt := tt // Capture range variable